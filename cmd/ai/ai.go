@@ -4,11 +4,11 @@ Copyright © 2024 NAME HERE <EMAIL ADDRESS>
 package ai
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"net/http"
-	"time"
+	"os"
 
+	"github.com/itsiqbal/sun-cli/internal/llm"
 	"github.com/spf13/cobra"
 )
 
@@ -16,60 +16,117 @@ var (
 	prompt string
 )
 
+const (
+	colorReset  = "\033[0m"
+	colorBold   = "\033[1m"
+	colorBlue   = "\033[34m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorRed    = "\033[31m"
+	colorDim    = "\033[2m"
+)
+
 // infoCmd represents the info command
 var AiCmd = &cobra.Command{
 	Use:   "ai",
 	Short: "A command pallet related to ai searches",
-	Long:  ``,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println(prompt)
-		url := "http://localhost:11434/api/chat"
-		jsonData := []byte(fmt.Sprintf(`{
-		"model": "llama3",
-		"messages": [
-			{
-				"role": "user",
-				"content": "%s & Reply should be in 25 words only"
-			}
-		],
-		"stream": false
-	}`, prompt))
-
-		// Create a new HTTP request
-		req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-		if err != nil {
-			fmt.Println("Error creating request:", err)
-			return
-		}
+	Long: `Sends a prompt to a chat model and prints the reply.
 
-		// Set the Content-Type header to application/json
-		req.Header.Set("Content-Type", "application/json")
+The model server is read from ~/.config/sun-cli/ai-config.json and can be
+overridden per run:
 
-		// Create an HTTP client and set a timeout
-		client := &http.Client{Timeout: time.Second * 10}
+  --provider ollama   Ollama (default http://localhost:11434)
+  --provider openai   Any OpenAI-compatible /v1/chat/completions server:
+                      llama.cpp server, vLLM, LM Studio or a hosted API
 
-		// Send the HTTP request
-		resp, err := client.Do(req)
-		if err != nil {
-			fmt.Println("Error making request:", err)
-			return
-		}
-		// defer resp.Body.Close()
+The API key, if the server needs one, is read from the environment variable
+named by "apiKeyEnv" in the config, else SUN_AI_API_KEY, else OPENAI_API_KEY
+for the openai provider.
 
-		// Read and print the response
-		fmt.Printf("Response status: %s\n", resp.Status)
-		buf := new(bytes.Buffer)
-		if _, err := buf.ReadFrom(resp.Body); err != nil {
-			fmt.Printf("⚠️ Failed to read response body: %v\n", err)
+Replies are printed as they are generated; Ctrl-C stops one cleanly.
+
+Piped input and files given with --file are sent along as context, each
+between BEGIN/END marker lines. Binary files are skipped, and context over the
+limit ("contextLimit" in the config, or --context-limit) is cut from the
+middle, keeping the start and the end.
+
+The system prompt comes from --system, else the --persona prompt, else
+"system" in the config. Built-in personas are sre, reviewer, teacher and
+concise; "personas" in the config adds more or replaces them.
+
+With Ollama, sun ai models lists, pulls and removes models and sets the
+default one; a --model that is not installed is reported before sending.`,
+	Example: `  sun ai -p "what is a pod disruption budget"
+  sun ai -p "hello" --model qwen2.5:7b
+  sun ai -p "hello" --provider openai --base-url http://localhost:8080/v1 --model local
+  kubectl logs pod/api-7d9f | sun ai -p "why is this crashing?"
+  sun ai -p "review this" -f main.go -f go.mod --persona reviewer
+  sun ai run explain-error --var service=api
+  sun ai models pull qwen2.5:7b
+  sun ai open "prod logs for airasia"
+  sun ai cmd "find files over 1GB modified this week"
+  sun ai explain -- make deploy
+  sun ai chat --resume incident-42`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if prompt == "" {
+			return fmt.Errorf("no prompt. Pass one with --prompt")
 		}
-		fmt.Printf("Response body: %s\n", buf.String())
+		return quietExit(cmd, ask(prompt))
 	},
 }
 
+// ask sends a single prompt with the system prompt and any piped or attached
+// context, and prints the reply
+func ask(text string) error {
+	provider, cfg, err := newProvider()
+	if err != nil {
+		return err
+	}
+	if modelFlag != "" {
+		if err := validateModel(context.Background(), provider, cfg.Model); err != nil {
+			return err
+		}
+	}
+
+	system, err := systemPrompt(cfg)
+	if err != nil {
+		return err
+	}
+
+	attachments, err := readAttachments()
+	if err != nil {
+		return err
+	}
+	fitAttachments(attachments, cfg.ContextLimit)
+
+	var messages []llm.Message
+	if system != "" {
+		messages = append(messages, llm.Message{Role: llm.RoleSystem, Content: system})
+	}
+	messages = append(messages, llm.Message{Role: llm.RoleUser, Content: withContext(text, attachments)})
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	_, err = complete(ctx, provider, llm.Request{Model: cfg.Model, Messages: messages}, os.Stdout)
+	if interrupted(err) {
+		fmt.Fprintln(os.Stderr, "Interrupted")
+		return errInterrupted
+	}
+	return err
+}
+
 func init() {
 
 	AiCmd.Flags().StringVarP(&prompt, "prompt", "p", "", "write prompt to search")
-	fmt.Print(prompt)
+	AiCmd.Flags().StringArrayVarP(&fileFlags, "file", "f", nil, "Attach a file as context (repeatable)")
+	AiCmd.Flags().IntVar(&contextLimitFlag, "context-limit", 0, "Maximum bytes of piped and attached context (default 100000)")
+	AiCmd.PersistentFlags().StringVar(&providerFlag, "provider", "", "Model server type (ollama|openai)")
+	AiCmd.PersistentFlags().StringVar(&modelFlag, "model", "", "Model name")
+	AiCmd.PersistentFlags().StringVar(&baseURLFlag, "base-url", "", "Model server URL, e.g. http://localhost:8080/v1")
+	AiCmd.PersistentFlags().StringVar(&systemFlag, "system", "", "System prompt, overriding the one in the config")
+	AiCmd.PersistentFlags().StringVar(&personaFlag, "persona", "", "Use a named system prompt: sre, reviewer, teacher, concise or one from the config")
+	AiCmd.PersistentFlags().BoolVar(&noStreamFlag, "no-stream", false, "Print the reply once it is complete instead of as it is generated")
 
 	// AiCmd.AddCommand(weatherCmd)
 	// Here you will define your flags and configuration settings.
@@ -0,0 +1,366 @@
+// cmd/ai/chat.go
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/itsiqbal/sun-cli/internal/llm"
+	"github.com/itsiqbal/sun-cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// session is a chat conversation; named sessions are saved after every reply
+type session struct {
+	Name     string        `json:"name"`
+	Model    string        `json:"model"`
+	System   string        `json:"system,omitempty"`
+	Messages []llm.Message `json:"messages"`
+	Created  time.Time     `json:"created"`
+	Updated  time.Time     `json:"updated"`
+}
+
+// chat is a running REPL
+type chat struct {
+	provider llm.Provider
+	session  *session
+	failed   string // user message whose reply failed, for /retry
+}
+
+// namePattern keeps session and template names usable as file names
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+var (
+	// Chat flags
+	chatResume string
+)
+
+// chatCmd represents the ai chat command
+var chatCmd = &cobra.Command{
+	Use:   "chat",
+	Short: "Chat with a model in a REPL that keeps the conversation",
+	Long: `Opens an interactive chat. Every message is sent with the whole conversation
+so far, so the model can refer back to it.
+
+Commands:
+  /model [name]        Show or switch the model
+  /system [text|off]   Show, set or remove the system prompt
+  /clear               Forget the conversation (keeps model and system prompt)
+  /save [name]         Save the session; it is then saved after every reply
+  /retry               Ask for the last reply again
+  /help                Show the commands
+  /exit                Leave (or Ctrl-D)
+
+Ctrl-C stops a reply that is being generated. Sessions are stored in
+~/.config/sun-cli/ai-sessions/ and continued with --resume.`,
+	Example: `  sun ai chat
+  sun ai chat --model qwen2.5:7b
+  sun ai chat --resume incident-42`,
+	Args: cobra.NoArgs,
+	RunE: runChatCommand,
+}
+
+func init() {
+	chatCmd.Flags().StringVar(&chatResume, "resume", "", "Continue a saved session")
+
+	AiCmd.AddCommand(chatCmd)
+}
+
+// runChatCommand runs the REPL until /exit or Ctrl-D
+func runChatCommand(cmd *cobra.Command, args []string) error {
+	provider, cfg, err := newProvider()
+	if err != nil {
+		return err
+	}
+
+	if modelFlag != "" {
+		if err := validateModel(context.Background(), provider, cfg.Model); err != nil {
+			return err
+		}
+	}
+
+	system, err := systemPrompt(cfg)
+	if err != nil {
+		return err
+	}
+
+	s := &session{Model: cfg.Model, System: system, Created: time.Now()}
+	if chatResume != "" {
+		if s, err = loadSession(chatResume); err != nil {
+			return err
+		}
+		// Flags override what the session was saved with
+		if modelFlag != "" || s.Model == "" {
+			s.Model = cfg.Model
+		}
+		if systemFlag != "" || personaFlag != "" {
+			s.System = system
+		}
+	}
+
+	historyFile := filepath.Join(configDir, "ai-chat-history")
+	if err := makePrivate(historyFile); err != nil {
+		return err
+	}
+
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:          colorBold + colorBlue + "› " + colorReset,
+		HistoryFile:     historyFile,
+		InterruptPrompt: "^C",
+		EOFPrompt:       "/exit",
+	})
+	if err != nil {
+		return err
+	}
+	defer rl.Close()
+
+	c := &chat{provider: provider, session: s}
+	c.printHeader()
+
+	for {
+		line, err := rl.Readline()
+		if errors.Is(err, readline.ErrInterrupt) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "/"):
+			if c.command(line) {
+				return nil
+			}
+		default:
+			c.send(line)
+		}
+	}
+}
+
+// printHeader shows the model and, for a resumed session, where it left off
+func (c *chat) printHeader() {
+	s := c.session
+	fmt.Printf("\n%s%s💬 Chat with %s%s %s(%s)%s\n", colorBold, colorBlue, s.Model, colorReset, colorDim, c.provider.Name(), colorReset)
+	if s.Name != "" {
+		fmt.Printf("%sResumed %s: %d messages, last updated %s%s\n", colorDim, s.Name, len(s.Messages), s.Updated.Local().Format("2006-01-02 15:04"), colorReset)
+	}
+	fmt.Printf("%s/help for commands, Ctrl-D to exit%s\n\n", colorDim, colorReset)
+}
+
+// send adds a user message and prints the reply. A failed or interrupted
+// reply takes the message back out of the history, for /retry.
+func (c *chat) send(text string) {
+	s := c.session
+	s.Messages = append(s.Messages, llm.Message{Role: llm.RoleUser, Content: text})
+
+	ctx, stop := signalContext()
+	resp, err := complete(ctx, c.provider, llm.Request{Model: s.Model, Messages: s.request()}, os.Stdout)
+	stop()
+
+	if err != nil {
+		s.Messages = s.Messages[:len(s.Messages)-1]
+		c.failed = text
+		if interrupted(err) {
+			fmt.Printf("%sInterrupted. /retry to ask again%s\n\n", colorDim, colorReset)
+		} else {
+			fmt.Printf("%sError: %v%s\n%s/retry to ask again%s\n\n", colorRed, err, colorReset, colorDim, colorReset)
+		}
+		return
+	}
+
+	c.failed = ""
+	s.Messages = append(s.Messages, llm.Message{Role: llm.RoleAssistant, Content: resp.Content})
+	fmt.Println()
+	c.autosave()
+}
+
+// command runs a slash command and reports whether the REPL should exit
+func (c *chat) command(line string) bool {
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	s := c.session
+
+	switch name {
+	case "/exit", "/quit":
+		return true
+
+	case "/help":
+		fmt.Printf("%s/model [name]  /system [text|off]  /clear  /save [name]  /retry  /exit%s\n\n", colorDim, colorReset)
+
+	case "/model":
+		if arg == "" {
+			fmt.Printf("Model: %s %s(%s)%s\n\n", s.Model, colorDim, c.provider.Name(), colorReset)
+			break
+		}
+		if err := validateModel(context.Background(), c.provider, arg); err != nil {
+			fmt.Printf("%sError: %v%s\n\n", colorRed, err, colorReset)
+			break
+		}
+		s.Model = arg
+		fmt.Printf("%s✓ Model: %s%s\n\n", colorGreen, arg, colorReset)
+		c.autosave()
+
+	case "/system":
+		switch arg {
+		case "":
+			if s.System == "" {
+				fmt.Printf("%sNo system prompt%s\n\n", colorDim, colorReset)
+			} else {
+				fmt.Printf("System: %s\n\n", s.System)
+			}
+		case "off":
+			s.System = ""
+			fmt.Printf("%s✓ System prompt removed%s\n\n", colorGreen, colorReset)
+			c.autosave()
+		default:
+			s.System = arg
+			fmt.Printf("%s✓ System prompt set%s\n\n", colorGreen, colorReset)
+			c.autosave()
+		}
+
+	case "/clear":
+		s.Messages = nil
+		c.failed = ""
+		fmt.Printf("%s✓ Conversation cleared%s\n\n", colorGreen, colorReset)
+		c.autosave()
+
+	case "/save":
+		if arg == "" {
+			arg = s.Name
+		}
+		if arg == "" {
+			fmt.Printf("%sUsage: /save <name>%s\n\n", colorYellow, colorReset)
+			break
+		}
+		if !namePattern.MatchString(arg) {
+			fmt.Printf("%sInvalid name '%s': use letters, digits, '.', '_' and '-'%s\n\n", colorYellow, arg, colorReset)
+			break
+		}
+		s.Name = arg
+		if err := saveSession(s); err != nil {
+			fmt.Printf("%sError: %v%s\n\n", colorRed, err, colorReset)
+			break
+		}
+		fmt.Printf("%s✓ Saved as %s%s %s(sun ai chat --resume %s)%s\n\n", colorGreen, arg, colorReset, colorDim, arg, colorReset)
+
+	case "/retry":
+		text := c.failed
+		if text == "" {
+			n := len(s.Messages)
+			if n < 2 || s.Messages[n-1].Role != llm.RoleAssistant || s.Messages[n-2].Role != llm.RoleUser {
+				fmt.Printf("%sNothing to retry%s\n\n", colorDim, colorReset)
+				break
+			}
+			text = s.Messages[n-2].Content
+			s.Messages = s.Messages[:n-2]
+		}
+		c.send(text)
+
+	default:
+		fmt.Printf("%sUnknown command %s. /help lists the commands%s\n\n", colorYellow, name, colorReset)
+	}
+	return false
+}
+
+// autosave saves named sessions, warning when that fails
+func (c *chat) autosave() {
+	if c.session.Name == "" {
+		return
+	}
+	if err := saveSession(c.session); err != nil {
+		fmt.Fprintf(os.Stderr, "%sWarning: Unable to save session: %v%s\n", colorYellow, err, colorReset)
+	}
+}
+
+// request returns the messages to send: the system prompt, then the history
+func (s *session) request() []llm.Message {
+	messages := make([]llm.Message, 0, len(s.Messages)+1)
+	if s.System != "" {
+		messages = append(messages, llm.Message{Role: llm.RoleSystem, Content: s.System})
+	}
+	return append(messages, s.Messages...)
+}
+
+// makePrivate creates path readable only by the user, or restricts it if it
+// exists. The chat history holds prompts, which often contain secrets, and
+// readline would create it world-readable.
+func makePrivate(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create history file: %w", err)
+	}
+	f.Close()
+	if err := os.Chmod(path, 0600); err != nil {
+		return fmt.Errorf("failed to restrict history file: %w", err)
+	}
+	return nil
+}
+
+// sessionPath returns the file a named session is stored in
+func sessionPath(name string) string {
+	return filepath.Join(sessionsDir, name+".json")
+}
+
+// saveSession writes a session; conversations may quote logs or code, so
+// the file is only readable by the user
+func saveSession(s *session) error {
+	s.Updated = time.Now()
+	if err := os.MkdirAll(sessionsDir, 0700); err != nil {
+		return fmt.Errorf("failed to create sessions directory: %w", err)
+	}
+	if err := store.WriteJSON(sessionPath(s.Name), s, 0600); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// loadSession reads a saved session, listing the saved ones when it is missing
+func loadSession(name string) (*session, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid session name '%s'", name)
+	}
+
+	var s session
+	err := store.ReadJSON(sessionPath(name), &s)
+	if errors.Is(err, fs.ErrNotExist) {
+		names := sessionNames()
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no session '%s'. Save one with /save <name> in sun ai chat", name)
+		}
+		return nil, fmt.Errorf("no session '%s'. Saved sessions: %s", name, strings.Join(names, ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	s.Name = name
+	return &s, nil
+}
+
+// sessionNames lists the saved sessions
+func sessionNames() []string {
+	entries, _ := os.ReadDir(sessionsDir)
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() && namePattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
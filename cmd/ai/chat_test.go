@@ -0,0 +1,47 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMakePrivate(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name   string
+		create bool
+	}{
+		{"new file", false},
+		{"world-readable file", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name)
+			if tt.create {
+				if err := os.WriteFile(path, []byte("prompt\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := makePrivate(path); err != nil {
+				t.Fatal(err)
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if perm := info.Mode().Perm(); perm != 0600 {
+				t.Errorf("perm = %o, want 600", perm)
+			}
+			if tt.create {
+				if data, _ := os.ReadFile(path); string(data) != "prompt\n" {
+					t.Errorf("content = %q, want the history kept", data)
+				}
+			}
+		})
+	}
+}
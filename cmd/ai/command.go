@@ -0,0 +1,438 @@
+// cmd/ai/command.go
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/chzyer/readline"
+	"github.com/itsiqbal/sun-cli/internal/llm"
+	"github.com/itsiqbal/sun-cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// commandInstructions is the system prompt of sun ai cmd; %s is the shell
+// the command runs in
+const commandInstructions = `You turn a request into one shell command for %s. Answer with one JSON object and nothing else:
+
+{"command": "...", "explanation": "..."}
+
+- command is a single line that can be pasted into the shell as it is. Chain steps with && or pipes rather than giving several commands.
+- explanation says in one or two sentences what the command does and what each unusual flag is for.
+- Prefer standard tools that are installed by default. Never add sudo unless the request needs it.
+- If the request cannot be done with a shell command, answer {"error": "<short reason>"}.`
+
+// Risk levels of a suggested command
+const (
+	riskLow    = "low"
+	riskMedium = "medium" // worth a second look
+	riskHigh   = "high"   // destroys data or changes a system; run needs typing yes
+)
+
+// riskRule flags a command pattern. Of the rules sharing a tool, only the
+// first that matches is reported.
+type riskRule struct {
+	pattern *regexp.Regexp
+	reason  string
+	high    bool
+	tool    string
+}
+
+// riskRules are checked against every suggested command
+var riskRules = []riskRule{
+	{regexp.MustCompile(`\brm\s+(-\S*\s+)*(-[^-\s]*[rRf]|--(recursive|force)\b)`), "deletes files recursively or without asking (rm -r/-f)", true, "rm"},
+	{regexp.MustCompile(`\brm\s`), "deletes files (rm)", false, "rm"},
+	{regexp.MustCompile(`\bfind\b.*\s-delete\b`), "deletes the files it finds (find -delete)", true, ""},
+	{regexp.MustCompile(`\bdd\b.*\bof=`), "writes raw data to a file or device (dd)", true, ""},
+	{regexp.MustCompile(`\b(mkfs(\.\w+)?|wipefs|shred|fdisk|parted)\b`), "formats or wipes disks", true, ""},
+	{regexp.MustCompile(`>\s*/dev/(sd|hd|nvme|disk|mmcblk)`), "writes to a disk device", true, ""},
+	{regexp.MustCompile(`(?i)\bdrop\s+(table|database|schema|index|user)\b`), "drops database objects (DROP)", true, ""},
+	{regexp.MustCompile(`(?i)\btruncate\s`), "empties a table or file (TRUNCATE)", true, ""},
+	{regexp.MustCompile(`(?i)\bdelete\s+from\b`), "deletes rows (DELETE FROM)", true, ""},
+	{regexp.MustCompile(`\bgit\s+push\b.*\s(--force\S*|-f)\b`), "force-pushes, rewriting remote history", true, ""},
+	{regexp.MustCompile(`\bgit\s+(reset\s+--hard|clean\s+-\S*f)`), "discards local changes (git reset --hard / clean)", true, ""},
+	{regexp.MustCompile(`\b(kubectl|helm)\s+(\S+\s+)*(delete|uninstall|drain)\b`), "deletes cluster resources", true, ""},
+	{regexp.MustCompile(`\bgcloud\b.*\s(delete|reset)\b`), "deletes or resets cloud resources", true, ""},
+	{regexp.MustCompile(`\bterraform\s+(destroy|apply\b.*-auto-approve)`), "changes infrastructure without review", true, ""},
+	{regexp.MustCompile(`\b(shutdown|reboot|halt|poweroff)\b`), "shuts down or restarts the machine", true, ""},
+	{regexp.MustCompile(`:\(\)\s*\{.*\|.*&\s*\}`), "fork bomb", true, ""},
+	{regexp.MustCompile(`\b(curl|wget)\b.*\|\s*(sudo\s+)?(ba|z|k)?sh\b`), "runs a script downloaded from the internet", true, ""},
+	{regexp.MustCompile(`\bchmod\s+(-\S+\s+)*0?777\b`), "makes files writable by everyone", false, ""},
+	{regexp.MustCompile(`\b(chmod|chown|chgrp)\s+(-\S*\s+)*-\S*R`), "changes ownership or permissions recursively", false, ""},
+	{regexp.MustCompile(`\bsudo\b`), "runs as root (sudo)", false, ""},
+	{regexp.MustCompile(`\bkill(all)?\s+(-9|-KILL|-SIGKILL)\b`), "kills processes without letting them clean up", false, ""},
+	{regexp.MustCompile(`\b(mv|cp)\s+(-\S*\s+)*-\S*f`), "overwrites files without asking", false, ""},
+}
+
+// redirectPattern matches an output redirection that truncates a file, but
+// not >> or 2>&1
+var redirectPattern = regexp.MustCompile(`(?:^|[^>&0-9])[0-9]?>\s*([^\s>&|;]+)`)
+
+// singleQuoted matches a single-quoted shell string
+var singleQuoted = regexp.MustCompile(`'[^']*'`)
+
+// riskAssessment is what assessRisk found in a command
+type riskAssessment struct {
+	Level   string
+	Reasons []string
+}
+
+// commandEntry is one line of the accepted command log
+type commandEntry struct {
+	Time      time.Time `json:"time"`
+	Request   string    `json:"request"`
+	Command   string    `json:"command"`
+	Suggested string    `json:"suggested,omitempty"` // the model's command, when it was edited
+	Action    string    `json:"action"`              // run or copy
+	Risk      string    `json:"risk"`
+	ExitCode  *int      `json:"exitCode,omitempty"`
+}
+
+// commandCmd represents the ai cmd command
+var commandCmd = &cobra.Command{
+	Use:   "cmd <request>",
+	Short: "Suggest a shell command, then run, edit or copy it",
+	Long: `Asks the model for one shell command that does what the request says, with an
+explanation, and shows it with a risk assessment: deleting files, writing to
+disks, dropping tables, force-pushing and the like are flagged.
+
+Nothing runs without confirmation. Choose to run the command, edit it first,
+copy it to the clipboard or cancel; commands flagged high risk need typing
+"yes" to run. Without a terminal the command is only printed.
+
+Commands that are run or copied are logged to ~/.config/sun-cli/ai-commands.jsonl.`,
+	Example: `  sun ai cmd "find files over 1GB modified this week"
+  sun ai cmd "which process is listening on port 8080"
+  sun ai cmd "delete merged git branches" --model qwen2.5:7b`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCommandCommand,
+}
+
+func init() {
+	AiCmd.AddCommand(commandCmd)
+}
+
+// runCommandCommand asks for a command and lets the user decide what to do with it
+func runCommandCommand(cmd *cobra.Command, args []string) error {
+	request := strings.Join(args, " ")
+
+	provider, cfg, err := newProvider()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	if modelFlag != "" {
+		if err := validateModel(ctx, provider, cfg.Model); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%s🤖 Asking %s...%s\n", colorDim, cfg.Model, colorReset)
+	resp, err := provider.Chat(ctx, llm.Request{
+		Model: cfg.Model,
+		Messages: []llm.Message{
+			{Role: llm.RoleSystem, Content: fmt.Sprintf(commandInstructions, shellDescription())},
+			{Role: llm.RoleUser, Content: request},
+		},
+		JSON: true,
+	})
+	if interrupted(err) {
+		fmt.Fprintln(os.Stderr, "Interrupted")
+		return quietExit(cmd, errInterrupted)
+	}
+	if err != nil {
+		return err
+	}
+
+	suggestion, err := parseSuggestion(resp.Content)
+	if err != nil {
+		return err
+	}
+
+	command := suggestion.Command
+	risk := assessRisk(command)
+	printSuggestion(command, suggestion.Explanation, risk)
+
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		fmt.Fprintf(os.Stderr, "%sNot a terminal, so the command was not run%s\n", colorDim, colorReset)
+		return nil
+	}
+
+	rl, err := readline.NewEx(&readline.Config{InterruptPrompt: "^C"})
+	if err != nil {
+		return err
+	}
+	defer rl.Close()
+
+	entry := commandEntry{Request: request}
+	for {
+		rl.SetPrompt(fmt.Sprintf("%sRun, edit, copy or cancel?%s [r/e/c/N] ", colorBold, colorReset))
+		choice, err := rl.Readline()
+		if err != nil {
+			choice = "" // Ctrl-C or Ctrl-D cancels
+		}
+
+		switch strings.ToLower(strings.TrimSpace(choice)) {
+		case "r", "run":
+			if risk.Level == riskHigh && !confirmHighRisk(rl) {
+				fmt.Printf("%sCancelled%s\n", colorDim, colorReset)
+				return nil
+			}
+			rl.Close()
+			code := runCommand(command)
+			entry.Action, entry.ExitCode = "run", &code
+			logCommand(entry, command, suggestion.Command, risk)
+			if code != 0 {
+				return quietExit(cmd, &ExitError{Code: code})
+			}
+			return nil
+
+		case "e", "edit":
+			rl.SetPrompt(colorBold + "$ " + colorReset)
+			edited, err := rl.ReadlineWithDefault(command)
+			if err != nil {
+				edited = ""
+			}
+			next, nextRisk, ok := applyEdit(edited)
+			if !ok {
+				fmt.Printf("%sKept the command as it was%s\n", colorDim, colorReset)
+				continue
+			}
+			command, risk = next, nextRisk
+			printSuggestion(command, "", risk)
+
+		case "c", "copy":
+			if err := copyToClipboard(command); err != nil {
+				fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+				continue
+			}
+			fmt.Printf("%s✓ Copied to the clipboard%s\n", colorGreen, colorReset)
+			entry.Action = "copy"
+			logCommand(entry, command, suggestion.Command, risk)
+			return nil
+
+		case "", "n", "no", "cancel", "q":
+			fmt.Printf("%sCancelled%s\n", colorDim, colorReset)
+			return nil
+
+		default:
+			fmt.Printf("%sAnswer r, e, c or n%s\n", colorDim, colorReset)
+		}
+	}
+}
+
+// suggestion is the JSON reply expected from the model
+type suggestion struct {
+	Command     string `json:"command"`
+	Explanation string `json:"explanation"`
+	Error       string `json:"error,omitempty"`
+}
+
+// parseSuggestion decodes the model's reply, allowing for a Markdown code
+// fence around it
+func parseSuggestion(content string) (*suggestion, error) {
+	text := strings.TrimSpace(content)
+	if fenced, ok := strings.CutPrefix(text, "```"); ok {
+		fenced = strings.TrimPrefix(fenced, "json")
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(fenced), "```"))
+	}
+
+	var s suggestion
+	if err := json.Unmarshal([]byte(text), &s); err != nil {
+		return nil, fmt.Errorf("the model did not answer with JSON (%v): %s", err, truncate(text, 200))
+	}
+	if s.Error != "" {
+		return nil, fmt.Errorf("the model has no command for this: %s", s.Error)
+	}
+	s.Command = strings.TrimSpace(s.Command)
+	if s.Command == "" {
+		return nil, fmt.Errorf("the model's answer has no command: %s", truncate(text, 200))
+	}
+	// Escape sequences could redraw the terminal to hide what would run
+	if i := strings.IndexFunc(s.Command, hiddenRune); i >= 0 {
+		return nil, fmt.Errorf("the model's command contains a control character (%q), so it is not shown or run: %q",
+			[]rune(s.Command[i:])[0], truncate(s.Command, 200))
+	}
+	return &s, nil
+}
+
+// hiddenRune reports whether r is a control or bidirectional formatting
+// character, which can make a command look different from what it does
+func hiddenRune(r rune) bool {
+	return (unicode.IsControl(r) && r != '\t') || unicode.Is(unicode.Bidi_Control, r)
+}
+
+// applyEdit returns an edited command with its risk assessed afresh, or
+// false when the edit is blank and the command stays as it was
+func applyEdit(edited string) (string, riskAssessment, bool) {
+	command := strings.TrimSpace(edited)
+	if command == "" {
+		return "", riskAssessment{}, false
+	}
+	return command, assessRisk(command), true
+}
+
+// assessRisk flags the destructive and risky patterns in a command
+func assessRisk(command string) riskAssessment {
+	risk := riskAssessment{Level: riskLow}
+	flag := func(reason string, high bool) {
+		for _, r := range risk.Reasons {
+			if r == reason {
+				return
+			}
+		}
+		risk.Reasons = append(risk.Reasons, reason)
+		switch {
+		case high:
+			risk.Level = riskHigh
+		case risk.Level == riskLow:
+			risk.Level = riskMedium
+		}
+	}
+
+	matched := map[string]bool{}
+	for _, rule := range riskRules {
+		if (rule.tool != "" && matched[rule.tool]) || !rule.pattern.MatchString(command) {
+			continue
+		}
+		matched[rule.tool] = true
+		flag(rule.reason, rule.high)
+	}
+
+	// A > inside quotes, e.g. in an awk program, is not a redirection
+	unquoted := singleQuoted.ReplaceAllString(command, "''")
+	for _, m := range redirectPattern.FindAllStringSubmatch(unquoted, -1) {
+		if target := m[1]; target != "/dev/null" && !strings.HasPrefix(target, "/dev/sd") {
+			flag("overwrites "+target+" with >", false)
+		}
+	}
+	return risk
+}
+
+// printSuggestion shows a command with its explanation and risk
+func printSuggestion(command, explanation string, risk riskAssessment) {
+	fmt.Printf("\n  %s%s$ %s%s\n", colorBold, colorBlue, command, colorReset)
+	if explanation != "" {
+		fmt.Printf("\n  %s\n", explanation)
+	}
+
+	switch risk.Level {
+	case riskHigh:
+		fmt.Printf("\n  %s%s🛑 Risk: high%s\n", colorBold, colorRed, colorReset)
+	case riskMedium:
+		fmt.Printf("\n  %s⚠️  Risk: medium%s\n", colorYellow, colorReset)
+	default:
+		fmt.Printf("\n  %s✓ Risk: low%s %s(no destructive patterns found)%s\n", colorGreen, colorReset, colorDim, colorReset)
+	}
+	for _, reason := range risk.Reasons {
+		fmt.Printf("    • %s\n", reason)
+	}
+	fmt.Println()
+}
+
+// confirmHighRisk asks for "yes" before a high-risk command runs
+func confirmHighRisk(rl *readline.Instance) bool {
+	rl.SetPrompt(fmt.Sprintf("%sThis command is flagged high risk. Type yes to run it:%s ", colorRed, colorReset))
+	answer, err := rl.Readline()
+	return err == nil && strings.EqualFold(strings.TrimSpace(answer), "yes")
+}
+
+// runCommand runs a command line through the platform shell, attached to the
+// terminal, and returns its exit code
+func runCommand(command string) int {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.Command("cmd", "/C", command)
+	} else {
+		c = exec.Command("sh", "-c", command)
+	}
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	fmt.Println()
+	err := c.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		fmt.Fprintf(os.Stderr, "%sExited with status %d%s\n", colorDim, exitErr.ExitCode(), colorReset)
+		return exitErr.ExitCode()
+	default:
+		fmt.Fprintf(os.Stderr, "%sError: %v%s\n", colorRed, err, colorReset)
+		return 1
+	}
+}
+
+// shellDescription names the shell and platform commands run in, for the model
+func shellDescription() string {
+	if runtime.GOOS == "windows" {
+		return "cmd.exe on Windows"
+	}
+	platform := "Linux"
+	if runtime.GOOS == "darwin" {
+		platform = "macOS (BSD userland tools)"
+	}
+	return "POSIX sh on " + platform
+}
+
+// copyToClipboard puts text on the system clipboard using the platform's tool
+func copyToClipboard(text string) error {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		candidates = [][]string{{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}}
+	}
+
+	for _, args := range candidates {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		c := exec.Command(args[0], args[1:]...)
+		c.Stdin = strings.NewReader(text)
+		if err := c.Run(); err != nil {
+			return fmt.Errorf("%s failed: %w", args[0], err)
+		}
+		return nil
+	}
+	return fmt.Errorf("no clipboard tool found (install wl-clipboard, xclip or xsel)")
+}
+
+// logCommand appends an accepted command to the command log
+func logCommand(entry commandEntry, command, suggested string, risk riskAssessment) {
+	entry.Time = time.Now().UTC()
+	entry.Command = command
+	if command != suggested {
+		entry.Suggested = suggested
+	}
+	entry.Risk = risk.Level
+
+	// Keep && and > readable in the log
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(entry); err != nil {
+		return
+	}
+	if err := store.Append(filepath.Join(configDir, "ai-commands.jsonl"), data.Bytes(), 0600); err != nil {
+		fmt.Fprintf(os.Stderr, "%sWarning: Unable to log the command: %v%s\n", colorYellow, err, colorReset)
+	}
+}
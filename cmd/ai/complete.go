@@ -0,0 +1,81 @@
+// cmd/ai/complete.go
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/itsiqbal/sun-cli/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Output flags
+	noStreamFlag bool
+)
+
+// ExitError makes sun exit with Code once a command has reported what
+// happened. Commands return it instead of calling os.Exit, so their deferred
+// cleanup still runs.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// errInterrupted is returned by a command stopped with Ctrl-C; 130 is the
+// status shells use for SIGINT
+var errInterrupted = &ExitError{Code: 130}
+
+// quietExit stops cobra from printing an *ExitError and the usage after it
+func quietExit(cmd *cobra.Command, err error) error {
+	var exit *ExitError
+	if errors.As(err, &exit) {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+	}
+	return err
+}
+
+// signalContext returns a context canceled by Ctrl-C or SIGTERM
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// complete sends the request and writes the reply to w: token by token as it
+// arrives, or all at once with --no-stream. The output always ends with a
+// newline, also when the reply is cut short.
+func complete(ctx context.Context, provider llm.Provider, req llm.Request, w io.Writer) (*llm.Response, error) {
+	if noStreamFlag {
+		resp, err := provider.Chat(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintln(w, strings.TrimRight(resp.Content, "\n"))
+		return resp, nil
+	}
+
+	endsWithNewline := true
+	resp, err := provider.ChatStream(ctx, req, func(token string) error {
+		endsWithNewline = strings.HasSuffix(token, "\n")
+		_, err := io.WriteString(w, token)
+		return err
+	})
+	if !endsWithNewline {
+		fmt.Fprintln(w)
+	}
+	return resp, err
+}
+
+// interrupted reports whether err comes from Ctrl-C canceling the context
+func interrupted(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,172 @@
+// cmd/ai/config.go
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/itsiqbal/sun-cli/internal/llm"
+	"github.com/itsiqbal/sun-cli/internal/store"
+)
+
+// Config is the AI configuration stored in ~/.config/sun-cli/ai-config.json
+type Config struct {
+	Provider string `json:"provider"`          // ollama or openai
+	Model    string `json:"model"`             // model name as the server knows it
+	BaseURL  string `json:"baseUrl,omitempty"` // empty for the provider's default
+	// APIKeyEnv names the environment variable holding the API key, so the
+	// key itself never lands in the config file
+	APIKeyEnv string `json:"apiKeyEnv,omitempty"`
+	// ContextLimit caps piped and attached context in bytes (default 100000)
+	ContextLimit int `json:"contextLimit,omitempty"`
+	// System is the default system prompt; Personas are extra named system
+	// prompts for --persona
+	System   string            `json:"system,omitempty"`
+	Personas map[string]string `json:"personas,omitempty"`
+}
+
+// Environment variables read for the API key when apiKeyEnv is not set
+const (
+	apiKeyEnv       = "SUN_AI_API_KEY"
+	openAIAPIKeyEnv = "OPENAI_API_KEY"
+)
+
+var (
+	// Provider flags, overriding the config file
+	providerFlag string
+	modelFlag    string
+	baseURLFlag  string
+
+	configDir    string
+	configFile   string
+	sessionsDir  string
+	templatesDir string
+)
+
+func init() {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Unable to determine home directory\n")
+		os.Exit(1)
+	}
+
+	configDir = filepath.Join(homeDir, ".config", "sun-cli")
+	configFile = filepath.Join(configDir, "ai-config.json")
+	sessionsDir = filepath.Join(configDir, "ai-sessions")
+	templatesDir = filepath.Join(configDir, "ai-templates")
+}
+
+// defaultConfig is a local Ollama server with llama3
+func defaultConfig() Config {
+	return Config{Provider: llm.ProviderOllama, Model: "llama3"}
+}
+
+// loadConfig reads the config file and applies the provider flags
+func loadConfig() (Config, error) {
+	cfg, err := readConfig()
+	if err != nil {
+		return cfg, err
+	}
+
+	if providerFlag != "" {
+		cfg.Provider = providerFlag
+	}
+	if modelFlag != "" {
+		cfg.Model = modelFlag
+	}
+	if baseURLFlag != "" {
+		cfg.BaseURL = baseURLFlag
+	}
+	if contextLimitFlag > 0 {
+		cfg.ContextLimit = contextLimitFlag
+	}
+	if cfg.ContextLimit <= 0 {
+		cfg.ContextLimit = defaultContextLimit
+	}
+	return cfg, nil
+}
+
+// readConfig reads the config file as stored, creating it with the defaults
+// when it does not exist
+func readConfig() (Config, error) {
+	cfg := defaultConfig()
+
+	err := store.ReadJSON(configFile, &cfg)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return cfg, saveConfig(cfg)
+	case err != nil:
+		var corrupt *store.CorruptError
+		if errors.As(err, &corrupt) {
+			return cfg, fmt.Errorf("config file is corrupted, kept a copy at %s: %w", corrupt.Backup, corrupt.Err)
+		}
+		return cfg, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return cfg, nil
+}
+
+// saveConfig atomically writes the config file
+func saveConfig(cfg Config) error {
+	if err := store.WriteJSON(configFile, cfg, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// updateConfig applies edit to the config file under its lock, so a
+// concurrent write cannot be lost. A missing file starts from the defaults.
+func updateConfig(edit func(cfg *Config)) error {
+	cfg := defaultConfig()
+	err := store.UpdateJSON(configFile, &cfg, 0644, func() error {
+		edit(&cfg)
+		return nil
+	})
+	if err != nil {
+		var corrupt *store.CorruptError
+		if errors.As(err, &corrupt) {
+			return fmt.Errorf("config file is corrupted, kept a copy at %s: %w", corrupt.Backup, corrupt.Err)
+		}
+		return fmt.Errorf("failed to update config file: %w", err)
+	}
+	return nil
+}
+
+// apiKey returns the API key from the environment
+func (c Config) apiKey() string {
+	if c.APIKeyEnv != "" {
+		return os.Getenv(c.APIKeyEnv)
+	}
+	if key := os.Getenv(apiKeyEnv); key != "" {
+		return key
+	}
+	if strings.EqualFold(c.Provider, llm.ProviderOpenAI) {
+		return os.Getenv(openAIAPIKeyEnv)
+	}
+	return ""
+}
+
+// newProvider loads the config and returns the provider it selects
+func newProvider() (llm.Provider, Config, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, cfg, err
+	}
+	if cfg.Model == "" {
+		return nil, cfg, fmt.Errorf("no model configured. Pass --model or set \"model\" in %s", configFile)
+	}
+
+	// No client timeout: long answers take as long as they take, and Ctrl-C
+	// cancels through the context
+	provider, err := llm.New(llm.Config{
+		Provider: cfg.Provider,
+		BaseURL:  cfg.BaseURL,
+		APIKey:   cfg.apiKey(),
+	})
+	if err != nil {
+		return nil, cfg, err
+	}
+	return provider, cfg, nil
+}
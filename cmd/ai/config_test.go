@@ -0,0 +1,43 @@
+package ai
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/itsiqbal/sun-cli/internal/store"
+)
+
+func TestUpdateConfig(t *testing.T) {
+	saved := configFile
+	configFile = filepath.Join(t.TempDir(), "ai-config.json")
+	t.Cleanup(func() { configFile = saved })
+
+	t.Run("missing file starts from the defaults", func(t *testing.T) {
+		if err := updateConfig(func(cfg *Config) { cfg.Model = "mistral" }); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := readConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Model != "mistral" || cfg.Provider != defaultConfig().Provider {
+			t.Errorf("config = %+v, want the default provider with mistral", cfg)
+		}
+	})
+
+	t.Run("other fields are kept", func(t *testing.T) {
+		if err := store.WriteJSON(configFile, Config{Provider: "openai", Model: "gpt-4o", BaseURL: "http://x"}, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := updateConfig(func(cfg *Config) { cfg.Model = "gpt-4o-mini" }); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := readConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Model != "gpt-4o-mini" || cfg.Provider != "openai" || cfg.BaseURL != "http://x" {
+			t.Errorf("config = %+v, want only the model changed", cfg)
+		}
+	})
+}
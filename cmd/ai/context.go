@@ -0,0 +1,156 @@
+// cmd/ai/context.go
+package ai
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultContextLimit caps the attachments sent with a prompt, in bytes
+// (about 25k tokens), so a large log does not overflow the model's context
+const defaultContextLimit = 100_000
+
+// attachment is a piece of context sent along with the prompt
+type attachment struct {
+	Name    string // "stdin" or the file path
+	Content string
+	Omitted int // bytes cut from the middle to fit the size limit, if any
+}
+
+var (
+	// Context flags
+	fileFlags        []string
+	contextLimitFlag int
+)
+
+// readAttachments reads piped stdin and the --file attachments. Binary
+// files are skipped with a warning.
+func readAttachments() ([]attachment, error) {
+	var attachments []attachment
+
+	if !isTerminal(os.Stdin) {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		switch {
+		case len(bytes.TrimSpace(data)) == 0:
+		case isBinary(data):
+			fmt.Fprintf(os.Stderr, "%sWarning: Skipped stdin: binary data%s\n", colorYellow, colorReset)
+		default:
+			attachments = append(attachments, attachment{Name: "stdin", Content: string(data)})
+		}
+	}
+
+	for _, path := range fileFlags {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if isBinary(data) {
+			fmt.Fprintf(os.Stderr, "%sWarning: Skipped %s: binary file%s\n", colorYellow, path, colorReset)
+			continue
+		}
+		attachments = append(attachments, attachment{Name: path, Content: string(data)})
+	}
+
+	return attachments, nil
+}
+
+// isBinary guesses whether data is binary: a NUL byte or invalid UTF-8 near
+// the start gives it away
+func isBinary(data []byte) bool {
+	head := data[:min(len(data), 8000)]
+	if bytes.IndexByte(head, 0) >= 0 {
+		return true
+	}
+	// The cut may split a multi-byte character at the end
+	for i := 0; i < utf8.UTFMax && len(head) > 0 && !utf8.Valid(head); i++ {
+		head = head[:len(head)-1]
+	}
+	return !utf8.Valid(head)
+}
+
+// fitAttachments shares limit bytes between the attachments: small ones are
+// kept whole and what they leave is split evenly between the larger ones,
+// which keep their start and end with the middle cut out
+func fitAttachments(attachments []attachment, limit int) {
+	order := make([]int, len(attachments))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(attachments[order[a]].Content) < len(attachments[order[b]].Content)
+	})
+
+	remaining := limit
+	for n, i := range order {
+		a := &attachments[i]
+		share := remaining / (len(order) - n)
+		size := len(a.Content)
+		if size <= share {
+			remaining -= size
+			continue
+		}
+		a.Content, a.Omitted = truncateMiddle(a.Content, share)
+		fmt.Fprintf(os.Stderr, "%sWarning: Cut %d of %d bytes from the middle of %s to fit the %d byte context limit%s\n",
+			colorYellow, a.Omitted, size, a.Name, limit, colorReset)
+		// Charge the whole share, not the notice, so the larger ones split evenly
+		remaining -= share
+	}
+}
+
+// truncateMiddle keeps about size bytes of s, half from the start and half
+// from the end, cut at line breaks where possible, with a notice in place of
+// the middle. It returns the text and the number of bytes left out.
+func truncateMiddle(s string, size int) (string, int) {
+	head := s[:size/2]
+	start := len(s) - size/2
+	tail := s[start:]
+	if i := strings.LastIndexByte(head, '\n'); i > 0 {
+		head = head[:i+1]
+	}
+	// The tail is cut at a line break unless it already starts a line
+	if i := strings.IndexByte(tail, '\n'); s[start-1] != '\n' && i >= 0 && i < len(tail)-1 {
+		tail = tail[i+1:]
+	}
+	head = strings.ToValidUTF8(head, "")
+	tail = strings.ToValidUTF8(tail, "")
+
+	omitted := len(s) - len(head) - len(tail)
+	if !strings.HasSuffix(head, "\n") {
+		head += "\n"
+	}
+	return fmt.Sprintf("%s[... %d bytes omitted to fit the context limit ...]\n%s", head, omitted, tail), omitted
+}
+
+// withContext appends the attachments to the prompt, each between marker
+// lines so the model can tell them apart from the question
+func withContext(prompt string, attachments []attachment) string {
+	if len(attachments) == 0 {
+		return prompt
+	}
+
+	var b strings.Builder
+	b.WriteString(prompt)
+	for _, a := range attachments {
+		fmt.Fprintf(&b, "\n\n----- BEGIN %s -----\n", a.Name)
+		b.WriteString(a.Content)
+		if !strings.HasSuffix(a.Content, "\n") {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "----- END %s -----", a.Name)
+	}
+	return b.String()
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,155 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// numberedLines returns n lines "line 000" to "line <n-1>"
+func numberedLines(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "line %03d\n", i)
+	}
+	return b.String()
+}
+
+func TestFitAttachments(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes []int
+		limit int
+		// wantKept is the size each attachment keeps, -1 when it is cut to
+		// about wantShare bytes
+		wantKept  []int
+		wantShare int
+	}{
+		{"all fit", []int{100, 200}, 1000, []int{100, 200}, 0},
+		{"even split", []int{5000, 5000}, 1000, []int{-1, -1}, 500},
+		{"small ones leave room", []int{4000, 100, 6000}, 1000, []int{-1, 100, -1}, 450},
+		{"one large", []int{300, 200, 9000}, 1000, []int{300, 200, -1}, 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attachments := make([]attachment, len(tt.sizes))
+			for i, size := range tt.sizes {
+				attachments[i] = attachment{Name: fmt.Sprint(i), Content: strings.Repeat("x", size)}
+			}
+
+			fitAttachments(attachments, tt.limit)
+
+			for i, a := range attachments {
+				kept := len(a.Content)
+				if a.Omitted > 0 {
+					kept = tt.sizes[i] - a.Omitted
+				}
+				switch want := tt.wantKept[i]; {
+				case want >= 0 && (kept != want || a.Omitted != 0):
+					t.Errorf("attachment %d kept %d bytes (%d omitted), want all %d", i, kept, a.Omitted, want)
+				case want < 0 && kept != tt.wantShare:
+					t.Errorf("attachment %d kept %d bytes, want an even share of %d", i, kept, tt.wantShare)
+				}
+			}
+		})
+	}
+}
+
+func TestTruncateMiddle(t *testing.T) {
+	tests := []struct {
+		name        string
+		s           string
+		size        int
+		wantHead    string
+		wantTail    string
+		wantOmitted int
+	}{
+		{
+			// Lines are 9 bytes: the head stops after line 010 (99 bytes of
+			// 100), the tail starts at line 089 after a partial line
+			name:        "cut at line breaks",
+			s:           numberedLines(100),
+			size:        200,
+			wantHead:    numberedLines(11),
+			wantTail:    strings.TrimPrefix(numberedLines(100), numberedLines(89)),
+			wantOmitted: 900 - 99 - 99,
+		},
+		{
+			// 180 bytes from the end is the start of line 080
+			name:        "tail on a line start",
+			s:           numberedLines(100),
+			size:        360,
+			wantHead:    numberedLines(20),
+			wantTail:    strings.TrimPrefix(numberedLines(100), numberedLines(80)),
+			wantOmitted: 900 - 180 - 180,
+		},
+		{
+			name:        "no line breaks",
+			s:           strings.Repeat("x", 1000),
+			size:        100,
+			wantHead:    strings.Repeat("x", 50),
+			wantTail:    strings.Repeat("x", 50),
+			wantOmitted: 900,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, omitted := truncateMiddle(tt.s, tt.size)
+			if omitted != tt.wantOmitted {
+				t.Errorf("omitted = %d, want %d", omitted, tt.wantOmitted)
+			}
+
+			notice := fmt.Sprintf("[... %d bytes omitted to fit the context limit ...]\n", tt.wantOmitted)
+			head, tail, ok := strings.Cut(got, notice)
+			if !ok {
+				t.Fatalf("no omitted-bytes notice in:\n%s", got)
+			}
+			if strings.TrimSuffix(head, "\n") != strings.TrimSuffix(tt.wantHead, "\n") {
+				t.Errorf("head = %q, want %q", head, tt.wantHead)
+			}
+			if tail != tt.wantTail {
+				t.Errorf("tail = %q, want %q", tail, tt.wantTail)
+			}
+		})
+	}
+}
+
+func TestTruncateMiddleKeepsUTF8Valid(t *testing.T) {
+	s := strings.Repeat("é", 500) // 2 bytes each
+	got, omitted := truncateMiddle(s, 101)
+	if !utf8.ValidString(got) {
+		t.Errorf("result is not valid UTF-8: %q", got)
+	}
+	if kept := len(s) - omitted; kept != 100 {
+		t.Errorf("kept %d bytes, want 100: the split characters are dropped", kept)
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"empty", nil, false},
+		{"text", []byte("hello\nworld\n"), false},
+		{"UTF-8 text", []byte("héllo wörld ✓\n"), false},
+		{"NUL byte", []byte("PK\x03\x04\x00\x00"), true},
+		{"invalid UTF-8", []byte("abc\xff\xfedef"), true},
+		{"two-byte character split at the head", []byte(strings.Repeat("a", 7999) + "é" + "rest"), false},
+		{"three-byte character split at the head", []byte(strings.Repeat("a", 7998) + "€" + "rest"), false},
+		{"invalid byte just before the cut", []byte(strings.Repeat("a", 7990) + "\xff" + strings.Repeat("a", 100)), true},
+		{"NUL after the head", []byte(strings.Repeat("a", 8000) + "\x00"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBinary(tt.data); got != tt.want {
+				t.Errorf("isBinary = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+// cmd/ai/explain.go
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/itsiqbal/sun-cli/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+// explainInstructions is the system prompt of sun ai explain
+const explainInstructions = `You diagnose failed shell commands for a developer. From the command line, how it ended (exit status or signal) and the end of its output:
+- name the most likely cause first, quoting the output line that shows it;
+- then give concrete fixes: commands to run or changes to make, most likely first;
+- keep it short, and say so when the output is not enough to tell.`
+
+// maxTailLine caps a line kept by tailBuffer, so one huge line (minified
+// JSON, a progress bar without newlines) cannot crowd out the rest
+const maxTailLine = 2000
+
+var (
+	// Explain flags
+	tailLines int
+)
+
+// explainCmd represents the ai explain command
+var explainCmd = &cobra.Command{
+	Use:   "explain -- <command> [args...]",
+	Short: "Run a command and explain why it failed",
+	Long: `Runs a command with its output shown as usual. If it exits with a non-zero
+status, the command line, the exit status and the last lines of its output
+are sent to the model, which explains the likely cause and suggests fixes.
+
+Put the command after --, so its flags are not taken as flags of sun. A
+single quoted argument is run by the shell, allowing pipes and &&. sun exits
+with the command's exit status, or 128 plus the signal number when a signal
+killed it.
+
+The command's output goes through a pipe to be copied, so the command does
+not see a terminal: tools may drop colours and progress bars or buffer their
+output. Options such as --color=always bring colours back.`,
+	Example: `  sun ai explain -- make deploy
+  sun ai explain --tail 200 -- go test ./...
+  sun ai explain "npm ci && npm run build"
+  sun ai explain --persona sre -- kubectl rollout status deploy/api`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runExplainCommand,
+}
+
+func init() {
+	explainCmd.Flags().IntVar(&tailLines, "tail", 100, "Number of output lines sent to the model")
+	AiCmd.AddCommand(explainCmd)
+}
+
+// runExplainCommand runs the command and explains a failure
+func runExplainCommand(cmd *cobra.Command, args []string) error {
+	if tailLines < 1 {
+		return fmt.Errorf("--tail must be at least 1")
+	}
+
+	// Check the provider before running anything, so a bad config does
+	// not surface only after a long build
+	provider, cfg, err := newProvider()
+	if err != nil {
+		return err
+	}
+	system, err := systemPrompt(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Ctrl-C goes to the command; sun waits for it and then stops
+	ctx, stop := signalContext()
+	defer stop()
+
+	if modelFlag != "" {
+		if err := validateModel(ctx, provider, cfg.Model); err != nil {
+			return err
+		}
+	}
+
+	commandLine := formatCommandLine(args)
+	tail := newTailBuffer(tailLines)
+	start := time.Now()
+	status, err := runTee(args, tail)
+	if err != nil {
+		return err
+	}
+	if status.success() {
+		return nil
+	}
+	// Only Ctrl-C or SIGTERM sent to sun is an interruption; a command
+	// killed by another signal (SIGKILL from the OOM killer, SIGSEGV) failed
+	if ctx.Err() != nil {
+		fmt.Fprintf(os.Stderr, "\n%s%s was interrupted%s\n", colorDim, commandLine, colorReset)
+		return quietExit(cmd, errInterrupted)
+	}
+
+	fmt.Fprintf(os.Stderr, "\n%s%s✗ %s %s after %s%s\n", colorBold, colorRed, commandLine, status,
+		time.Since(start).Round(time.Second), colorReset)
+
+	output := tail.String()
+	prompt := fmt.Sprintf("This command failed: it %s.\n\n    %s\n\nIt ran in %s on %s. ",
+		status, commandLine, workingDir(), runtime.GOOS)
+	var attachments []attachment
+	if strings.TrimSpace(output) == "" {
+		prompt += "It printed nothing. Why might it have failed, and how do I fix it?"
+	} else {
+		name := "output"
+		if n := tail.Dropped(); n > 0 {
+			name = fmt.Sprintf("output (last %d lines, %d earlier lines not shown)", tailLines, n)
+		}
+		attachments = append(attachments, attachment{Name: name, Content: output})
+		fitAttachments(attachments, cfg.ContextLimit)
+		prompt += "Its output is below. Why did it fail, and how do I fix it?"
+	}
+
+	// One system message: some chat templates keep only the first
+	instructions := explainInstructions
+	if system != "" {
+		instructions = system + "\n\n" + explainInstructions
+	}
+	messages := []llm.Message{
+		{Role: llm.RoleSystem, Content: instructions},
+		{Role: llm.RoleUser, Content: withContext(prompt, attachments)},
+	}
+
+	fmt.Printf("\n%s%s🤖 Explanation%s %s(%s)%s\n\n", colorBold, colorBlue, colorReset, colorDim, cfg.Model, colorReset)
+	if _, err := complete(ctx, provider, llm.Request{Model: cfg.Model, Messages: messages}, os.Stdout); err != nil {
+		if interrupted(err) {
+			fmt.Fprintln(os.Stderr, "Interrupted")
+		} else {
+			fmt.Fprintf(os.Stderr, "%sError: %v%s\n", colorRed, err, colorReset)
+		}
+	}
+
+	return quietExit(cmd, &ExitError{Code: status.exitCode()})
+}
+
+// exitStatus is how a command ended: with an exit code, or killed by a signal
+type exitStatus struct {
+	Code   int
+	Signal syscall.Signal // set when a signal killed the command
+}
+
+func (s exitStatus) success() bool {
+	return s.Code == 0 && s.Signal == 0
+}
+
+// exitCode returns the status a shell reports: 128 plus the signal number
+// for a killed command
+func (s exitStatus) exitCode() int {
+	if s.Signal != 0 {
+		return 128 + int(s.Signal)
+	}
+	return s.Code
+}
+
+// String describes the status after the command line, e.g. "was killed by
+// SIGKILL (killed)"
+func (s exitStatus) String() string {
+	if s.Signal != 0 {
+		return "was killed by " + signalName(s.Signal)
+	}
+	return fmt.Sprintf("exited with status %d", s.Code)
+}
+
+// signalNames are the usual names of the signals that end processes
+var signalNames = map[syscall.Signal]string{
+	syscall.SIGHUP:  "SIGHUP",
+	syscall.SIGINT:  "SIGINT",
+	syscall.SIGQUIT: "SIGQUIT",
+	syscall.SIGILL:  "SIGILL",
+	syscall.SIGTRAP: "SIGTRAP",
+	syscall.SIGABRT: "SIGABRT",
+	syscall.SIGBUS:  "SIGBUS",
+	syscall.SIGFPE:  "SIGFPE",
+	syscall.SIGKILL: "SIGKILL",
+	syscall.SIGSEGV: "SIGSEGV",
+	syscall.SIGPIPE: "SIGPIPE",
+	syscall.SIGALRM: "SIGALRM",
+	syscall.SIGTERM: "SIGTERM",
+}
+
+// signalName names a signal with its description, e.g. "SIGSEGV
+// (segmentation fault)", or "signal 31 (bad system call)" when unlisted
+func signalName(sig syscall.Signal) string {
+	name, ok := signalNames[sig]
+	if !ok {
+		name = fmt.Sprintf("signal %d", int(sig))
+	}
+	return fmt.Sprintf("%s (%s)", name, sig)
+}
+
+// runTee runs a command attached to the terminal, copying its output into
+// tail as well, and returns how it ended
+func runTee(args []string, tail *tailBuffer) (exitStatus, error) {
+	var c *exec.Cmd
+	switch {
+	case len(args) > 1 || !strings.ContainsAny(args[0], " \t|&;<>()$`"):
+		c = exec.Command(args[0], args[1:]...)
+	case runtime.GOOS == "windows":
+		c = exec.Command("cmd", "/C", args[0])
+	default:
+		c = exec.Command("sh", "-c", args[0])
+	}
+	c.Stdin = os.Stdin
+	c.Stdout = io.MultiWriter(os.Stdout, tail)
+	c.Stderr = io.MultiWriter(os.Stderr, tail)
+
+	err := c.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return exitStatus{}, nil
+	case errors.As(err, &exitErr):
+		// ExitCode is -1 for a killed process; the wait status has the signal
+		if ws, ok := exitErr.Sys().(interface {
+			Signaled() bool
+			Signal() syscall.Signal
+		}); ok && ws.Signaled() {
+			return exitStatus{Signal: ws.Signal()}, nil
+		}
+		return exitStatus{Code: exitErr.ExitCode()}, nil
+	case errors.Is(err, exec.ErrNotFound):
+		return exitStatus{}, fmt.Errorf("command not found: %s", args[0])
+	default:
+		return exitStatus{}, fmt.Errorf("failed to run %s: %w", args[0], err)
+	}
+}
+
+// formatCommandLine joins arguments, quoting those the shell would split
+func formatCommandLine(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'|&;<>()$`*?") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+// workingDir returns the current directory with the home directory as ~
+func workingDir() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "an unknown directory"
+	}
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(dir, home) {
+		dir = "~" + strings.TrimPrefix(dir, home)
+	}
+	return dir
+}
+
+// ansiPattern matches terminal colour and cursor escape sequences
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;?]*[A-Za-z]")
+
+// tailBuffer is an io.Writer keeping the last lines written to it, in a
+// ring. stdout and stderr share one, so their lines stay in order.
+type tailBuffer struct {
+	mu      sync.Mutex
+	lines   []string
+	next    int // ring index of the next line
+	full    bool
+	dropped int    // lines pushed out of the ring
+	partial []byte // the line being written
+	cr      bool   // the last byte was \r
+}
+
+func newTailBuffer(n int) *tailBuffer {
+	return &tailBuffer{lines: make([]string, n)}
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, b := range p {
+		if t.cr && b != '\n' {
+			// A lone \r redraws the line, as progress bars do: keep the last version
+			t.partial = t.partial[:0]
+		}
+		t.cr = b == '\r'
+		switch b {
+		case '\r':
+		case '\n':
+			t.push(string(t.partial))
+			t.partial = t.partial[:0]
+		default:
+			if len(t.partial) < maxTailLine {
+				t.partial = append(t.partial, b)
+			}
+		}
+	}
+	return len(p), nil
+}
+
+// push adds a complete line to the ring
+func (t *tailBuffer) push(line string) {
+	if t.full {
+		t.dropped++
+	}
+	t.lines[t.next] = line
+	t.next = (t.next + 1) % len(t.lines)
+	t.full = t.full || t.next == 0
+}
+
+// String returns the kept lines, oldest first, without colour codes
+func (t *tailBuffer) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var lines []string
+	if t.full {
+		lines = append(lines, t.lines[t.next:]...)
+	}
+	lines = append(lines, t.lines[:t.next]...)
+	if len(t.partial) > 0 {
+		lines = append(lines, string(t.partial))
+		if len(lines) > len(t.lines) {
+			lines = lines[1:]
+		}
+	}
+	return ansiPattern.ReplaceAllString(strings.Join(lines, "\n"), "")
+}
+
+// Dropped returns how many earlier lines are not in the buffer
+func (t *tailBuffer) Dropped() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.full && len(t.partial) > 0 {
+		return t.dropped + 1 // String makes room for the partial line
+	}
+	return t.dropped
+}
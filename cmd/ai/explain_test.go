@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestTailBuffer(t *testing.T) {
+	tests := []struct {
+		name        string
+		size        int
+		writes      []string
+		want        string
+		wantDropped int
+	}{
+		{"fewer lines than the ring", 3, []string{"a\nb\n"}, "a\nb", 0},
+		{"ring wraps", 3, []string{"1\n2\n3\n4\n5\n"}, "3\n4\n5", 2},
+		{"ring exactly full", 3, []string{"1\n2\n3\n"}, "1\n2\n3", 0},
+		{"lines split across writes", 3, []string{"hel", "lo\nwor", "ld\n"}, "hello\nworld", 0},
+		{"partial last line", 3, []string{"a\nb\nno newline"}, "a\nb\nno newline", 0},
+		{"partial line pushes out the oldest", 2, []string{"1\n2\n3\npartial"}, "3\npartial", 2},
+		{"carriage return redraws", 3, []string{"start\n 10%\r 50%\r100%\ndone\n"}, "start\n100%\ndone", 0},
+		{"redraw split across writes", 3, []string{"10%\r", "90%", "\r100%\n"}, "100%", 0},
+		{"CRLF line endings", 3, []string{"a\r\nb\r\n"}, "a\nb", 0},
+		{"colour codes stripped", 3, []string{"\x1b[31merror\x1b[0m: boom\n"}, "error: boom", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tail := newTailBuffer(tt.size)
+			for _, w := range tt.writes {
+				if n, err := tail.Write([]byte(w)); n != len(w) || err != nil {
+					t.Fatalf("Write = %d, %v; want %d, nil", n, err, len(w))
+				}
+			}
+			if got := tail.String(); got != tt.want {
+				t.Errorf("String = %q, want %q", got, tt.want)
+			}
+			if got := tail.Dropped(); got != tt.wantDropped {
+				t.Errorf("Dropped = %d, want %d", got, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestTailBufferCapsLongLines(t *testing.T) {
+	tail := newTailBuffer(2)
+	tail.Write([]byte(strings.Repeat("x", maxTailLine+500) + "\nnext\n"))
+
+	lines := strings.Split(tail.String(), "\n")
+	if len(lines) != 2 || len(lines[0]) != maxTailLine || lines[1] != "next" {
+		t.Errorf("got %d lines of %d and %q bytes, want the first capped at %d", len(lines), len(lines[0]), lines[len(lines)-1], maxTailLine)
+	}
+}
+
+func TestFormatCommandLine(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"make", "deploy"}, "make deploy"},
+		{[]string{"npm ci && npm run build"}, "npm ci && npm run build"},
+		{[]string{"grep", "-r", "hello world", "."}, "grep -r 'hello world' ."},
+		{[]string{"echo", "it's"}, `echo 'it'\''s'`},
+		{[]string{"ls", "*.go"}, "ls '*.go'"},
+		{[]string{"printf", ""}, "printf ''"},
+		{[]string{"sh", "-c", "echo $HOME | wc"}, "sh -c 'echo $HOME | wc'"},
+		{[]string{"go", "test", "./..."}, "go test ./..."},
+	}
+
+	for _, tt := range tests {
+		if got := formatCommandLine(tt.args); got != tt.want {
+			t.Errorf("formatCommandLine(%q) = %s, want %s", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	tests := []struct {
+		status   exitStatus
+		want     string
+		wantCode int
+	}{
+		{exitStatus{Code: 2}, "exited with status 2", 2},
+		{exitStatus{Signal: syscall.SIGKILL}, "was killed by SIGKILL (killed)", 137},
+		{exitStatus{Signal: syscall.SIGSEGV}, "was killed by SIGSEGV (segmentation fault)", 139},
+	}
+
+	for _, tt := range tests {
+		if got := tt.status.String(); got != tt.want {
+			t.Errorf("String = %q, want %q", got, tt.want)
+		}
+		if got := tt.status.exitCode(); got != tt.wantCode {
+			t.Errorf("%s: exitCode = %d, want %d", tt.want, got, tt.wantCode)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+// cmd/ai/models.go
+package ai
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/itsiqbal/sun-cli/internal/llm"
+	"github.com/itsiqbal/sun-cli/internal/ollama"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Model flags
+	modelsYes bool
+)
+
+// modelsCmd represents the ai models command
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "List, show, pull and remove Ollama models",
+	Long: `Manages the models installed on the Ollama server. Without a subcommand, lists
+them; the default model, used when --model is not given, is marked with *.`,
+	Example: `  sun ai models
+  sun ai models pull qwen2.5:7b
+  sun ai models default qwen2.5:7b
+  sun ai models show llama3
+  sun ai models rm llama2`,
+	Args: cobra.NoArgs,
+	RunE: runModelsListCommand,
+}
+
+var modelsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed models",
+	Args:  cobra.NoArgs,
+	RunE:  runModelsListCommand,
+}
+
+var modelsShowCmd = &cobra.Command{
+	Use:   "show <model>",
+	Short: "Show a model's details and parameters",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runModelsShowCommand,
+}
+
+var modelsPullCmd = &cobra.Command{
+	Use:   "pull <model>",
+	Short: "Download a model",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runModelsPullCommand,
+}
+
+var modelsRmCmd = &cobra.Command{
+	Use:     "rm <model>",
+	Aliases: []string{"remove", "delete"},
+	Short:   "Remove an installed model",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runModelsRmCommand,
+}
+
+var modelsDefaultCmd = &cobra.Command{
+	Use:   "default [model]",
+	Short: "Show or set the default model",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runModelsDefaultCommand,
+}
+
+func init() {
+	modelsRmCmd.Flags().BoolVarP(&modelsYes, "yes", "y", false, "Remove without asking for confirmation")
+
+	modelsCmd.AddCommand(modelsListCmd, modelsShowCmd, modelsPullCmd, modelsRmCmd, modelsDefaultCmd)
+	AiCmd.AddCommand(modelsCmd)
+}
+
+// ollamaClient returns the client of the configured provider, which must be Ollama
+func ollamaClient() (*ollama.Client, Config, error) {
+	provider, cfg, err := newProvider()
+	if err != nil {
+		return nil, cfg, err
+	}
+	o, ok := provider.(*llm.Ollama)
+	if !ok {
+		return nil, cfg, fmt.Errorf("model management needs the ollama provider, not %s", provider.Name())
+	}
+	return o.Client, cfg, nil
+}
+
+// runModelsListCommand lists the installed models
+func runModelsListCommand(cmd *cobra.Command, args []string) error {
+	client, cfg, err := ollamaClient()
+	if err != nil {
+		return err
+	}
+
+	models, err := client.List(context.Background())
+	if err != nil {
+		return err
+	}
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].Name < models[j].Name
+	})
+
+	fmt.Printf("\n%s%s🧠 Models%s %s(%s)%s\n\n", colorBold, colorBlue, colorReset, colorDim, client.BaseURL, colorReset)
+	if len(models) == 0 {
+		fmt.Printf("%sNone installed. Download one with sun ai models pull <model>%s\n\n", colorDim, colorReset)
+		return nil
+	}
+
+	width := len("NAME")
+	for _, m := range models {
+		width = max(width, len(m.Name))
+	}
+	fmt.Printf("%s  %-*s  %9s  %7s  %-7s  %s%s\n", colorDim, width, "NAME", "SIZE", "PARAMS", "QUANT", "MODIFIED", colorReset)
+
+	def, _ := ollama.MatchModel(models, cfg.Model)
+	for i, m := range models {
+		marker := " "
+		if def == &models[i] {
+			marker = colorGreen + "*" + colorReset
+		}
+		fmt.Printf("%s %-*s  %9s  %7s  %-7s  %s\n", marker, width, m.Name, formatBytes(m.Size),
+			m.Details.ParameterSize, m.Details.QuantizationLevel, formatAge(m.ModifiedAt))
+	}
+	if def == nil {
+		fmt.Printf("\n%s⚠️  The default model %s is not installed%s\n", colorYellow, cfg.Model, colorReset)
+	}
+	fmt.Println()
+	return nil
+}
+
+// runModelsShowCommand prints a model's details
+func runModelsShowCommand(cmd *cobra.Command, args []string) error {
+	client, _, err := ollamaClient()
+	if err != nil {
+		return err
+	}
+
+	info, err := client.Show(context.Background(), args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s%s🧠 %s%s\n", colorBold, colorBlue, args[0], colorReset)
+	field := func(label, value string) {
+		if value != "" {
+			fmt.Printf("%s  %-16s%s%s\n", colorDim, label+":", colorReset, value)
+		}
+	}
+	field("Family", info.Details.Family)
+	field("Parameters", info.Details.ParameterSize)
+	field("Quantization", info.Details.QuantizationLevel)
+	field("Format", info.Details.Format)
+	for key, value := range info.Info {
+		// The key is prefixed with the architecture, e.g. llama.context_length
+		if strings.HasSuffix(key, ".context_length") {
+			field("Context length", fmt.Sprint(value))
+		}
+	}
+	if license, _, _ := strings.Cut(strings.TrimSpace(info.License), "\n"); license != "" {
+		field("License", license)
+	}
+	field("System prompt", strings.TrimSpace(info.System))
+
+	if params := strings.TrimSpace(info.Parameters); params != "" {
+		fmt.Printf("\n%sParameters:%s\n", colorBold, colorReset)
+		for _, line := range strings.Split(params, "\n") {
+			fmt.Printf("  %s\n", strings.Join(strings.Fields(line), " "))
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+// runModelsPullCommand downloads a model with a progress bar
+func runModelsPullCommand(cmd *cobra.Command, args []string) error {
+	client, _, err := ollamaClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	bar := &pullBar{tty: isTerminal(os.Stdout)}
+	err = client.Pull(ctx, args[0], bar.update)
+	bar.finish()
+	if interrupted(err) {
+		fmt.Fprintln(os.Stderr, "Interrupted; pulling again resumes the download")
+		return quietExit(cmd, errInterrupted)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s✓ Pulled %s%s\n", colorGreen, args[0], colorReset)
+	return nil
+}
+
+// runModelsRmCommand removes a model after confirmation
+func runModelsRmCommand(cmd *cobra.Command, args []string) error {
+	client, cfg, err := ollamaClient()
+	if err != nil {
+		return err
+	}
+	name := args[0]
+
+	if !modelsYes {
+		if !isTerminal(os.Stdin) {
+			return fmt.Errorf("pass --yes to remove %s without a prompt", name)
+		}
+		fmt.Printf("Remove %s? [y/N] ", name)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			fmt.Printf("%sCancelled%s\n", colorDim, colorReset)
+			return nil
+		}
+	}
+
+	if err := client.Delete(context.Background(), name); err != nil {
+		return err
+	}
+	fmt.Printf("%s✓ Removed %s%s\n", colorGreen, name, colorReset)
+
+	if sameModel(cfg.Model, name) {
+		fmt.Printf("%s⚠️  %s was the default model. Choose another with sun ai models default <model>%s\n", colorYellow, name, colorReset)
+	}
+	return nil
+}
+
+// runModelsDefaultCommand shows the default model or sets it in the config
+func runModelsDefaultCommand(cmd *cobra.Command, args []string) error {
+	cfg, err := readConfig()
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		fmt.Println(cfg.Model)
+		return nil
+	}
+
+	provider, _, err := newProvider()
+	if err != nil {
+		return err
+	}
+	if err := validateModel(context.Background(), provider, args[0]); err != nil {
+		return err
+	}
+
+	if err := updateConfig(func(cfg *Config) { cfg.Model = args[0] }); err != nil {
+		return err
+	}
+	fmt.Printf("%s✓ Default model: %s%s\n", colorGreen, args[0], colorReset)
+	return nil
+}
+
+// validateModel checks that an Ollama server has the model, so a typo fails
+// with the list of installed models instead of an error mid-chat. Other
+// providers are not checked.
+func validateModel(ctx context.Context, provider llm.Provider, name string) error {
+	o, ok := provider.(*llm.Ollama)
+	if !ok {
+		return nil
+	}
+
+	models, err := o.Client.List(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := ollama.MatchModel(models, name); ok {
+		return nil
+	}
+
+	names := make([]string, len(models))
+	for i, m := range models {
+		names[i] = m.Name
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return fmt.Errorf("model '%s' is not installed. Pull it with: sun ai models pull %s", name, name)
+	}
+	return fmt.Errorf("model '%s' is not installed (installed: %s). Pull it with: sun ai models pull %s",
+		name, strings.Join(names, ", "), name)
+}
+
+// sameModel compares model names, treating a missing tag as ":latest"
+func sameModel(a, b string) bool {
+	_, ok := ollama.MatchModel([]ollama.Model{{Name: a}}, b)
+	if !ok {
+		_, ok = ollama.MatchModel([]ollama.Model{{Name: b}}, a)
+	}
+	return ok
+}
+
+// pullBar prints pull progress: a redrawn bar on a terminal, status lines
+// otherwise
+type pullBar struct {
+	tty    bool
+	status string
+	drawn  bool // a bar is on the current line
+}
+
+func (b *pullBar) update(p ollama.PullProgress) error {
+	if p.Total > 0 && b.tty {
+		const width = 30
+		// Completed can overshoot Total, e.g. when a layer is re-verified
+		completed := max(0, min(p.Completed, p.Total))
+		filled := int(completed * width / p.Total)
+		fmt.Printf("\r  %-26s %s%s%s%s %3d%%  %s / %s ", truncate(p.Status, 26),
+			colorGreen, strings.Repeat("█", filled), colorReset, strings.Repeat("░", width-filled),
+			completed*100/p.Total, formatBytes(completed), formatBytes(p.Total))
+		b.status, b.drawn = p.Status, true
+		return nil
+	}
+
+	if p.Status == b.status {
+		return nil
+	}
+	b.finish()
+	fmt.Printf("  %s\n", p.Status)
+	b.status = p.Status
+	return nil
+}
+
+// finish ends a bar line
+func (b *pullBar) finish() {
+	if b.drawn {
+		fmt.Println()
+		b.drawn = false
+	}
+}
+
+// truncate shortens s to n runes with an ellipsis
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// formatBytes formats a size in decimal units, as Ollama does
+func formatBytes(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
+
+// formatAge formats how long ago t was, e.g. "3d ago"
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 60*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	default:
+		return t.Local().Format("2006-01-02")
+	}
+}
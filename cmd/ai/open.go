@@ -0,0 +1,160 @@
+// cmd/ai/open.go
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/itsiqbal/sun-cli/cmd/gcp"
+	"github.com/itsiqbal/sun-cli/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+// openInstructions is the system prompt of sun ai open; the catalogue of
+// the gcp config is appended to it
+const openInstructions = `You pick a Google Cloud Console page for a request. Answer with one JSON object and nothing else:
+
+{"project": "...", "env": "...", "service": "...", "logFilter": "..."}
+
+- project, env and service must be names from the lists below, spelled exactly as there.
+- logFilter is optional: a Cloud Logging query such as severity>=ERROR or resource.labels.container_name="api". Only give it with the Logs Explorer service, and only when the request asks for specific logs.
+- If the request does not name a project or environment and the lists leave a choice, pick the most likely one.
+- If the request cannot be answered with these lists, answer {"error": "<short reason>"}.`
+
+// openAnswer is the JSON reply expected from the model
+type openAnswer struct {
+	gcp.Target
+	Error string `json:"error,omitempty"`
+}
+
+// openCmd represents the ai open command
+var openCmd = &cobra.Command{
+	Use:   "open <request>",
+	Short: "Open a GCP console page described in plain words",
+	Long: `Asks the model which project, environment and service of the gcp config a
+request means, checks the answer against the config and opens the page like
+sun gcp does. For Logs Explorer the model may also add a log filter.`,
+	Example: `  sun ai open "prod logs for airasia"
+  sun ai open "errors from the payments api in staging"
+  sun ai open "airasia dev cloud sql" --qr`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runOpenCommand,
+}
+
+func init() {
+	gcp.AddQRFlags(openCmd)
+	AiCmd.AddCommand(openCmd)
+}
+
+// runOpenCommand resolves the request with the model and opens the page
+func runOpenCommand(cmd *cobra.Command, args []string) error {
+	request := strings.Join(args, " ")
+
+	provider, cfg, err := newProvider()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	if modelFlag != "" {
+		if err := validateModel(ctx, provider, cfg.Model); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%s🤖 Resolving with %s...%s\n", colorDim, cfg.Model, colorReset)
+	resp, err := provider.Chat(ctx, llm.Request{
+		Model: cfg.Model,
+		Messages: []llm.Message{
+			{Role: llm.RoleSystem, Content: openInstructions + "\n\n" + gcpCatalogue()},
+			{Role: llm.RoleUser, Content: request},
+		},
+		JSON: true,
+	})
+	if interrupted(err) {
+		fmt.Fprintln(os.Stderr, "Interrupted")
+		return quietExit(cmd, errInterrupted)
+	}
+	if err != nil {
+		return err
+	}
+
+	answer, err := parseOpenAnswer(resp.Content)
+	if err != nil {
+		return err
+	}
+
+	target, err := gcp.ResolveTarget(answer.Target)
+	if err != nil {
+		return fmt.Errorf("the model answered %s/%s/%s, which is not in the gcp config: %w",
+			answer.Project, answer.Env, answer.Service, err)
+	}
+
+	fmt.Printf("\n%s%s🤖 Resolved%s %s%q%s\n", colorGreen, colorBold, colorReset, colorDim, request, colorReset)
+	fmt.Printf("  %s → %s → %s\n", target.Project, target.Env, target.Service)
+	if target.LogFilter != "" {
+		fmt.Printf("  %sfilter:%s %s\n", colorDim, colorReset, target.LogFilter)
+	} else if answer.LogFilter != "" {
+		fmt.Printf("  %sIgnoring the log filter, %s is not Logs Explorer%s\n", colorYellow, target.Service, colorReset)
+	}
+
+	return gcp.OpenTarget(target)
+}
+
+// parseOpenAnswer decodes the model's reply, allowing for a Markdown code
+// fence around it
+func parseOpenAnswer(content string) (*openAnswer, error) {
+	text := strings.TrimSpace(content)
+	if fenced, ok := strings.CutPrefix(text, "```"); ok {
+		fenced = strings.TrimPrefix(fenced, "json")
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(fenced), "```"))
+	}
+
+	var answer openAnswer
+	if err := json.Unmarshal([]byte(text), &answer); err != nil {
+		return nil, fmt.Errorf("the model did not answer with JSON (%v): %s", err, truncate(text, 200))
+	}
+	if answer.Error != "" {
+		return nil, fmt.Errorf("the model could not resolve the request: %s", answer.Error)
+	}
+	if answer.Project == "" || answer.Env == "" || answer.Service == "" {
+		return nil, fmt.Errorf("the model's answer is missing the project, env or service: %s", truncate(text, 200))
+	}
+	return &answer, nil
+}
+
+// gcpCatalogue lists the projects, environments and services of the gcp
+// config for the model
+func gcpCatalogue() string {
+	projects, services := gcp.Catalog()
+
+	var b strings.Builder
+	b.WriteString("Projects (name, GCP project ID prefix, environments):\n")
+	for _, p := range projects {
+		fmt.Fprintf(&b, "- %s (%s): %s", p.Name, p.ID, strings.Join(p.Environments, ", "))
+		if len(p.Labels) > 0 {
+			var labels []string
+			for k, v := range p.Labels {
+				labels = append(labels, k+"="+v)
+			}
+			sort.Strings(labels)
+			fmt.Fprintf(&b, " [%s]", strings.Join(labels, ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\nServices (name, category):\n")
+	for _, s := range services {
+		fmt.Fprintf(&b, "- %s", s.Name)
+		if s.Category != "" {
+			fmt.Fprintf(&b, " (%s)", s.Category)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/itsiqbal/sun-cli/cmd/gcp"
+)
+
+func TestParseOpenAnswer(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    gcp.Target
+		wantErr bool
+	}{
+		{
+			name:    "plain JSON",
+			content: `{"project": "AirAsia MOVE", "env": "prod", "service": "Logs Explorer", "logFilter": "severity>=ERROR"}`,
+			want:    gcp.Target{Project: "AirAsia MOVE", Env: "prod", Service: "Logs Explorer", LogFilter: "severity>=ERROR"},
+		},
+		{
+			name:    "fenced JSON",
+			content: "```json\n{\"project\": \"AirAsia MOVE\", \"env\": \"prod\", \"service\": \"Cloud Run\"}\n```",
+			want:    gcp.Target{Project: "AirAsia MOVE", Env: "prod", Service: "Cloud Run"},
+		},
+		{
+			name:    "fence without language",
+			content: "  ```\n{\"project\": \"p\", \"env\": \"e\", \"service\": \"s\"}\n```\n",
+			want:    gcp.Target{Project: "p", Env: "e", Service: "s"},
+		},
+		{name: "error", content: `{"error": "no such project"}`, wantErr: true},
+		{name: "missing service", content: `{"project": "p", "env": "e"}`, wantErr: true},
+		{name: "missing env", content: `{"project": "p", "service": "s"}`, wantErr: true},
+		{name: "not JSON", content: "Sure! Open the Logs Explorer.", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOpenAnswer(tt.content)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseOpenAnswer = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Target != tt.want {
+				t.Errorf("parseOpenAnswer = %+v, want %+v", got.Target, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// cmd/ai/prompts.go
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// builtinPersonas are system prompts selectable with --persona; personas in
+// the config add to these or replace them
+var builtinPersonas = map[string]string{
+	"sre": "You are a senior site reliability engineer. Answer practically: " +
+		"the most likely causes first, then the commands to confirm and fix them. " +
+		"Mention risks before suggesting anything destructive.",
+	"reviewer": "You are a careful code reviewer. Point out bugs, security issues, " +
+		"race conditions and unclear code, most important first, each with a concrete fix.",
+	"teacher": "You explain technical topics to a developer new to them: " +
+		"plain words, a short example, and no unexplained jargon.",
+	"concise": "Answer in at most 25 words.",
+}
+
+var (
+	// System prompt flags
+	systemFlag  string
+	personaFlag string
+)
+
+// systemPrompt returns the system prompt to use: --system, else the
+// --persona prompt, else the "system" prompt of the config
+func systemPrompt(cfg Config) (string, error) {
+	switch {
+	case systemFlag != "" && personaFlag != "":
+		return "", fmt.Errorf("use either --system or --persona")
+	case systemFlag != "":
+		return systemFlag, nil
+	case personaFlag != "":
+		if p, ok := cfg.Personas[personaFlag]; ok {
+			return p, nil
+		}
+		if p, ok := builtinPersonas[personaFlag]; ok {
+			return p, nil
+		}
+		return "", fmt.Errorf("unknown persona '%s'. Available: %s", personaFlag, strings.Join(personaNames(cfg), ", "))
+	default:
+		return cfg.System, nil
+	}
+}
+
+// personaNames lists the built-in and configured personas
+func personaNames(cfg Config) []string {
+	var names []string
+	for name := range builtinPersonas {
+		names = append(names, name)
+	}
+	for name := range cfg.Personas {
+		if _, ok := builtinPersonas[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
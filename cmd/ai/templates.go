@@ -0,0 +1,342 @@
+// cmd/ai/templates.go
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// templateExt is the extension of prompt template files
+const templateExt = ".tmpl"
+
+// builtinTemplates seed the template directory the first time it is used
+var builtinTemplates = map[string]string{
+	"explain-error": `{{/* Explain an error and how to fix it. Vars: error, service */}}
+Explain the following error{{with .service}} from the {{.}} service{{end}}: what it means, the likely causes and how to fix them.
+{{with .error}}
+Error: {{.}}
+{{end}}`,
+	"review": `{{/* Review code for bugs and risky changes. Vars: focus */}}
+Review the attached code{{with .focus}}, focusing on {{.}}{{end}}. List bugs, risky changes and unclear code, most important first, each with a concrete fix.
+`,
+	"summarize-logs": `{{/* Summarise logs: errors, timeline, affected components. Vars: service */}}
+Summarise the attached logs{{with .service}} of the {{.}} service{{end}}: the main errors and warnings, when they started, and which components are affected.
+`,
+}
+
+// templateSkeleton is the starting point for `sun ai templates edit` on a new name
+const templateSkeleton = `{{/* One-line description shown by sun ai templates list */}}
+{{- /*
+Write the prompt below. Variables given with --var name=value are available
+as {{.name}}; {{required "name" .name}} fails when one is missing and
+{{default "value" .name}} gives a fallback.
+*/}}
+Explain {{required "topic" .topic}} in a few sentences.
+`
+
+var (
+	// Run flags
+	varFlags []string
+)
+
+// runCmd represents the ai run command
+var runCmd = &cobra.Command{
+	Use:   "run <template>",
+	Short: "Send a prompt built from a template",
+	Long: `Renders a prompt template from ~/.config/sun-cli/ai-templates/ with the
+--var values and sends it like sun ai -p, including piped input and --file
+attachments.
+
+Templates use Go text/template syntax. Variables are {{.name}};
+{{required "name" .name}} fails when the variable is missing and
+{{default "value" .name}} gives a fallback.`,
+	Example: `  sun ai run explain-error --var service=api --var error="connection refused"
+  kubectl logs deploy/api | sun ai run summarize-logs --var service=api --persona sre
+  sun ai run review -f handler.go --var focus=concurrency`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRunCommand,
+}
+
+// templatesCmd represents the ai templates command
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List, show and edit prompt templates",
+	Long: `Prompt templates are text/template files in ~/.config/sun-cli/ai-templates/,
+used with sun ai run <name>. A few are created the first time.`,
+	Args: cobra.NoArgs,
+	RunE: runTemplatesListCommand,
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List prompt templates",
+	Args:  cobra.NoArgs,
+	RunE:  runTemplatesListCommand,
+}
+
+var templatesShowCmd = &cobra.Command{
+	Use:   "show <template>",
+	Short: "Print a prompt template",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTemplatesShowCommand,
+}
+
+var templatesEditCmd = &cobra.Command{
+	Use:   "edit <template>",
+	Short: "Edit or create a prompt template in $EDITOR",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTemplatesEditCommand,
+}
+
+func init() {
+	runCmd.Flags().StringArrayVar(&varFlags, "var", nil, "Template variable as name=value (repeatable)")
+	runCmd.Flags().StringArrayVarP(&fileFlags, "file", "f", nil, "Attach a file as context (repeatable)")
+	runCmd.Flags().IntVar(&contextLimitFlag, "context-limit", 0, "Maximum bytes of piped and attached context (default 100000)")
+
+	templatesCmd.AddCommand(templatesListCmd, templatesShowCmd, templatesEditCmd)
+	AiCmd.AddCommand(runCmd, templatesCmd)
+}
+
+// runRunCommand renders the template and sends it
+func runRunCommand(cmd *cobra.Command, args []string) error {
+	vars := map[string]string{}
+	for _, v := range varFlags {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid --var '%s'. Use name=value", v)
+		}
+		vars[name] = value
+	}
+
+	text, err := renderTemplate(args[0], vars)
+	if err != nil {
+		return err
+	}
+	return quietExit(cmd, ask(text))
+}
+
+// renderTemplate executes a template with the variables
+func renderTemplate(name string, vars map[string]string) (string, error) {
+	tmpl, err := loadTemplate(name)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, vars); err != nil {
+		var missing missingVarError
+		if errors.As(err, &missing) {
+			return "", fmt.Errorf("template %s needs a value for '%s'. Pass --var %s=...", name, string(missing), string(missing))
+		}
+		return "", fmt.Errorf("template %s: %w", name, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// loadTemplate parses a template file. Missing variables render as empty.
+func loadTemplate(name string) (*template.Template, error) {
+	data, err := readTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=zero").Funcs(templateFuncs).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// missingVarError is returned by the required template function
+type missingVarError string
+
+func (e missingVarError) Error() string {
+	return "missing variable " + string(e)
+}
+
+// templateFuncs are available in prompt templates
+var templateFuncs = template.FuncMap{
+	"required": func(name string, value any) (string, error) {
+		s, _ := value.(string)
+		if s == "" {
+			return "", missingVarError(name)
+		}
+		return s, nil
+	},
+	"default": func(fallback string, value any) string {
+		if s, _ := value.(string); s != "" {
+			return s
+		}
+		return fallback
+	},
+}
+
+// readTemplate reads a template file, listing the templates when it is missing
+func readTemplate(name string) ([]byte, error) {
+	if err := seedTemplates(); err != nil {
+		return nil, err
+	}
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid template name '%s'", name)
+	}
+
+	data, err := os.ReadFile(templatePath(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no template '%s'. Available: %s", name, strings.Join(templateNames(), ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	return data, nil
+}
+
+// templatePath returns the file of a template
+func templatePath(name string) string {
+	return filepath.Join(templatesDir, name+templateExt)
+}
+
+// seedTemplates creates the template directory with the built-in templates
+// the first time; after that the directory is the user's to change
+func seedTemplates() error {
+	if _, err := os.Stat(templatesDir); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+	for name, text := range builtinTemplates {
+		if err := os.WriteFile(templatePath(name), []byte(text), 0644); err != nil {
+			return fmt.Errorf("failed to write template %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// templateNames lists the templates
+func templateNames() []string {
+	entries, _ := os.ReadDir(templatesDir)
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), templateExt); ok && !e.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// templateDescription returns the text of a leading {{/* comment */}}
+func templateDescription(data []byte) string {
+	text := strings.TrimSpace(string(data))
+	rest, ok := strings.CutPrefix(text, "{{/*")
+	if !ok {
+		return ""
+	}
+	comment, _, ok := strings.Cut(rest, "*/}}")
+	if !ok {
+		return ""
+	}
+	return strings.Join(strings.Fields(comment), " ")
+}
+
+// runTemplatesListCommand lists the templates with their descriptions
+func runTemplatesListCommand(cmd *cobra.Command, args []string) error {
+	if err := seedTemplates(); err != nil {
+		return err
+	}
+
+	names := templateNames()
+	fmt.Printf("\n%s%s📝 Prompt templates%s %s(%s)%s\n\n", colorBold, colorBlue, colorReset, colorDim, templatesDir, colorReset)
+	if len(names) == 0 {
+		fmt.Printf("%sNone yet. Create one with sun ai templates edit <name>%s\n\n", colorDim, colorReset)
+		return nil
+	}
+
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	for _, name := range names {
+		data, _ := os.ReadFile(templatePath(name))
+		fmt.Printf("  %-*s  %s%s%s\n", width, name, colorDim, templateDescription(data), colorReset)
+	}
+	fmt.Println()
+	return nil
+}
+
+// runTemplatesShowCommand prints a template as it is stored
+func runTemplatesShowCommand(cmd *cobra.Command, args []string) error {
+	data, err := readTemplate(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(data))
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		fmt.Println()
+	}
+	return nil
+}
+
+// runTemplatesEditCommand opens a template in the editor, creating it from
+// a skeleton when new, and checks that it still parses afterwards
+func runTemplatesEditCommand(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid template name '%s': use letters, digits, '.', '_' and '-'", name)
+	}
+	if err := seedTemplates(); err != nil {
+		return err
+	}
+
+	path := templatePath(name)
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		if err := os.WriteFile(path, []byte(templateSkeleton), 0644); err != nil {
+			return fmt.Errorf("failed to create template: %w", err)
+		}
+	}
+
+	if err := openEditor(path); err != nil {
+		return err
+	}
+
+	if _, err := loadTemplate(name); err != nil {
+		return fmt.Errorf("%w\nRun sun ai templates edit %s again to fix it", err, name)
+	}
+	fmt.Printf("%s✓ Saved %s%s\n", colorGreen, path, colorReset)
+	return nil
+}
+
+// openEditor opens path in $VISUAL or $EDITOR and waits for it to close
+func openEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// The editor may come with arguments, e.g. "code --wait"
+	fields := strings.Fields(editor)
+	c := exec.Command(fields[0], append(fields[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+// cmd/gcp/cli.go
+package gcp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// CLI flags
+	cliRun bool
+)
+
+// cliCmd represents the gcp cli command
+var cliCmd = &cobra.Command{
+	Use:   "cli [project] [env] [service]",
+	Short: "Print the gcloud/kubectl/bq commands for a service",
+	Long: `Resolves a project, environment and service like the launcher does, then
+prints the service's "commands" from the config with the {project}, {id},
+{name} and {env} placeholders filled in, the same way as the console URL.
+The time range and location flags fill {start}, {end}, {region} and {zone};
+a command that uses one of them without a value is not printed.
+Values that the shell would split or expand are single-quoted, so do not
+quote placeholders in the config.
+
+Commands go to stdout and everything else to stderr, so the output can be
+piped. Missing arguments are asked for interactively. With --run, the
+commands are executed one by one after confirmation.`,
+	Example: `  sun gcp cli air prod sql
+  sun gcp cli air staging k8s --run
+  sun gcp cli air prod run --region asia-southeast1
+  sun gcp cli air prod logs | sh`,
+	Args: cobra.MaximumNArgs(3),
+	RunE: runCliCommand,
+}
+
+func init() {
+	cliCmd.Flags().BoolVar(&cliRun, "run", false, "Run the commands after confirmation")
+	addURLParamFlags(cliCmd)
+
+	GcpCmd.AddCommand(cliCmd)
+}
+
+// runCliCommand prints or runs the resolved commands of the selected service
+func runCliCommand(cmd *cobra.Command, args []string) error {
+	wizardOut = os.Stderr
+
+	if err := parseURLParamFlags(); err != nil {
+		return err
+	}
+
+	sel, err := resolveCliSelection(args)
+	if errors.Is(err, errCancelled) {
+		fmt.Fprintf(os.Stderr, "%sCancelled%s\n", colorDim, colorReset)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	commands, err := serviceCommands(sel.Project, sel.Env, sel.Service)
+	if err != nil {
+		return err
+	}
+	if len(commands) == 0 {
+		return fmt.Errorf("no commands configured for '%s'. Add \"commands\" to the service in %s",
+			sel.Service.Name, configFile)
+	}
+
+	fmt.Fprintf(os.Stderr, "\n%s%s⌨️  %s / %s / %s%s\n", colorBold, colorBlue,
+		sel.Project.Name, sel.Env, sel.Service.Name, colorReset)
+	for _, command := range commands {
+		fmt.Fprintln(cmd.OutOrStdout(), command)
+	}
+
+	if !cliRun {
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr)
+	if !confirm(fmt.Sprintf("Run %d command(s)", len(commands))) {
+		fmt.Fprintf(os.Stderr, "%sCancelled%s\n", colorDim, colorReset)
+		return nil
+	}
+
+	for _, command := range commands {
+		fmt.Fprintf(os.Stderr, "\n%s$ %s%s\n", colorDim, command, colorReset)
+		if err := runShell(command); err != nil {
+			return fmt.Errorf("command failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// resolveCliSelection matches all three arguments silently, keeping stdout
+// clean for piping, and asks for whatever is missing otherwise
+func resolveCliSelection(args []string) (*selection, error) {
+	if len(args) == 3 {
+		project, env, err := resolveProjectEnv(args[0], args[1])
+		if err != nil {
+			return nil, err
+		}
+		service := findMatchingService(availableServices(project, env), args[2])
+		if service == nil {
+			return nil, fmt.Errorf("no service matching '%s' in %s/%s", args[2], project.Name, env)
+		}
+		return &selection{Project: project, Env: env, Service: service}, nil
+	}
+
+	answers := make([]string, 3)
+	copy(answers, args)
+	return newWizard(answers[0], answers[1], answers[2]).run()
+}
+
+// serviceCommands resolves the placeholders in the commands of a service,
+// quoting the values for the shell. A placeholder without a value is an
+// error, so a half-filled command is never printed or run.
+func serviceCommands(project *Project, env string, service *Service) ([]string, error) {
+	vars := urlVariables(project)
+	vars["project"] = projectID(project, env)
+	vars["id"] = project.ID
+	vars["name"] = project.Name
+	vars["env"] = env
+
+	commands := make([]string, 0, len(service.Commands))
+	for _, command := range service.Commands {
+		filled, ok := fillTemplate(command, vars, shellQuote)
+		if !ok {
+			name := missingVariable(command, vars)
+			return nil, fmt.Errorf("no value for {%s} in a command of '%s'; %s",
+				name, service.Name, variableHints[name])
+		}
+		commands = append(commands, filled)
+	}
+	return commands, nil
+}
+
+// variableHints tells how to set each placeholder that can be empty
+var variableHints = map[string]string{
+	"start":    "set a time range with --since or --from",
+	"end":      "set a time range with --since or --from",
+	"range":    "set a time range with --since or --from",
+	"duration": "set a time range with --since",
+	"region":   "pass --region or --zone, or set the project's region",
+	"zone":     "pass --zone",
+	"query":    "it is only set by sun ai open",
+}
+
+// missingVariable returns the first placeholder of tmpl that is in vars
+// without a value
+func missingVariable(tmpl string, vars map[string]string) string {
+	for _, m := range placeholderPattern.FindAllStringSubmatch(tmpl, -1) {
+		if value, known := vars[m[1]]; known && value == "" {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// shellSafePattern matches a word the shell takes literally
+var shellSafePattern = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// shellQuote single-quotes s unless the shell would take it literally
+func shellQuote(s string) string {
+	if shellSafePattern.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// runShell runs a command line through the platform shell, attached to the terminal
+func runShell(command string) error {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.Command("cmd", "/C", command)
+	} else {
+		c = exec.Command("sh", "-c", command)
+	}
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
@@ -0,0 +1,74 @@
+package gcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestServiceCommands(t *testing.T) {
+	service := &Service{
+		Name: "Memorystore",
+		Commands: []string{
+			"gcloud redis instances list --project {project} --region {region}",
+			"echo {name}",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		project  Project
+		region   string
+		want     []string
+		wantErrs []string
+	}{
+		{
+			name:    "project region",
+			project: Project{Name: "Air Asia", ID: "air", Region: "asia-southeast1"},
+			want: []string{
+				"gcloud redis instances list --project air-prod --region asia-southeast1",
+				"echo 'Air Asia'",
+			},
+		},
+		{
+			name:    "region flag",
+			project: Project{Name: "air", ID: "air", Region: "asia-southeast1"},
+			region:  "us-central1",
+			want: []string{
+				"gcloud redis instances list --project air-prod --region us-central1",
+				"echo air",
+			},
+		},
+		{
+			name:     "no region",
+			project:  Project{Name: "air", ID: "air"},
+			wantErrs: []string{"{region}", "Memorystore", "--region"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := regionFlag
+			regionFlag = tt.region
+			t.Cleanup(func() { regionFlag = saved })
+
+			got, err := serviceCommands(&tt.project, "prod", service)
+			if tt.wantErrs != nil {
+				if err == nil {
+					t.Fatalf("commands = %q, want an error", got)
+				}
+				for _, want := range tt.wantErrs {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("err = %q, want it to mention %s", err, want)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("commands = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// cmd/gcp/env.go
+package gcp
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Env flags
+	envShell string
+)
+
+// envShells lists the supported shell syntaxes
+var envShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// envCmd represents the gcp env command
+var envCmd = &cobra.Command{
+	Use:   "env <project> <env>",
+	Short: "Print shell exports for a project environment",
+	Long: `Prints environment variables for a project environment in shell syntax,
+suitable for eval:
+
+  CLOUDSDK_CORE_PROJECT, GOOGLE_CLOUD_PROJECT  the GCP project ID
+  SUN_ENV                                      the environment name
+
+plus the project's "variables" from the config. Variable values may use the
+{project}, {id}, {name} and {env} placeholders.`,
+	Example: `  eval "$(sun gcp env air prod)"
+  sun gcp env air prod --shell fish | source
+  sun gcp env air prod --shell powershell | Invoke-Expression`,
+	Args: cobra.ExactArgs(2),
+	RunE: runEnvCommand,
+}
+
+func init() {
+	envCmd.Flags().StringVar(&envShell, "shell", "",
+		fmt.Sprintf("Shell syntax (%s); detected from $SHELL by default", strings.Join(envShells, "|")))
+
+	GcpCmd.AddCommand(envCmd)
+}
+
+// runEnvCommand prints the exports without any other output, so it can be eval'd
+func runEnvCommand(cmd *cobra.Command, args []string) error {
+	project, env, err := resolveProjectEnv(args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	shell := envShell
+	if shell == "" {
+		shell = detectShell()
+	}
+
+	vars, err := projectVariables(project, env)
+	if err != nil {
+		return err
+	}
+	return writeExports(cmd.OutOrStdout(), shell, vars)
+}
+
+// resolveProjectEnv matches a project and environment without printing anything
+func resolveProjectEnv(projectFilter, envFilter string) (*Project, string, error) {
+	project := findMatchingProject(projectFilter)
+	if project == nil {
+		return nil, "", fmt.Errorf("no project matching '%s'", projectFilter)
+	}
+	for _, env := range project.Environments {
+		if strings.EqualFold(env, envFilter) {
+			return project, env, nil
+		}
+	}
+	return nil, "", fmt.Errorf("invalid environment '%s' for project '%s'. Valid: %v",
+		envFilter, project.Name, project.Environments)
+}
+
+// variableNamePattern matches a name every supported shell takes as is;
+// anything else could smuggle shell code into the eval'd output
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// projectVariables returns the variables for a project environment, sorted by name
+func projectVariables(project *Project, env string) ([][2]string, error) {
+	vars := [][2]string{
+		{"CLOUDSDK_CORE_PROJECT", projectID(project, env)},
+		{"GOOGLE_CLOUD_PROJECT", projectID(project, env)},
+		{"SUN_ENV", env},
+	}
+
+	names := make([]string, 0, len(project.Variables))
+	for name := range project.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !variableNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid variable name '%s' in project '%s': use letters, digits and '_', not starting with a digit",
+				name, project.Name)
+		}
+		vars = append(vars, [2]string{name, resolvePlaceholders(project.Variables[name], project, env)})
+	}
+	return vars, nil
+}
+
+// detectShell guesses the shell syntax from the environment
+func detectShell() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		name := filepath.Base(shell)
+		for _, s := range envShells {
+			if name == s {
+				return s
+			}
+		}
+		return "bash"
+	}
+	if runtime.GOOS == "windows" {
+		return "powershell"
+	}
+	return "bash"
+}
+
+// writeExports writes the variables in the syntax of the given shell
+func writeExports(w io.Writer, shell string, vars [][2]string) error {
+	var line func(name, value string) string
+	switch strings.ToLower(shell) {
+	case "bash", "zsh", "sh":
+		line = func(name, value string) string {
+			return fmt.Sprintf("export %s='%s'", name, strings.ReplaceAll(value, "'", `'\''`))
+		}
+	case "fish":
+		line = func(name, value string) string {
+			value = strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value)
+			return fmt.Sprintf("set -gx %s '%s';", name, value)
+		}
+	case "powershell", "pwsh":
+		line = func(name, value string) string {
+			return fmt.Sprintf("$env:%s = '%s'", name, strings.ReplaceAll(value, "'", "''"))
+		}
+	default:
+		return fmt.Errorf("unknown shell '%s'. Valid: %s", shell, strings.Join(envShells, ", "))
+	}
+
+	for _, v := range vars {
+		if _, err := fmt.Fprintln(w, line(v[0], v[1])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+// cmd/gcp/export.go
+package gcp
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// catalogueEntry is a single project × environment × service console link
+type catalogueEntry struct {
+	Project     string `json:"project"`
+	ProjectID   string `json:"projectId"`
+	Environment string `json:"environment"`
+	Service     string `json:"service"`
+	URL         string `json:"url"`
+}
+
+var (
+	// Export flags
+	exportFormat string
+	exportOutput string
+)
+
+// exportFormats lists the supported export formats
+var exportFormats = []string{"bookmarks-html", "markdown", "csv", "json"}
+
+// exportCmd represents the gcp export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the launcher catalogue as bookmarks, Markdown, CSV or JSON",
+	Long: `Walks every project × environment × available service from the config and writes
+the console links in the requested format.
+
+Formats:
+  bookmarks-html   Netscape bookmark file (folders per project and environment)
+  markdown         Markdown link table for wikis
+  csv              Comma-separated values
+  json             JSON array`,
+	Example: `  sun gcp export --format bookmarks-html -o gcp-bookmarks.html
+  sun gcp export --format markdown > GCP.md
+  sun gcp export --format json | jq '.[].url'`,
+	Args: cobra.NoArgs,
+	RunE: runExportCommand,
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "bookmarks-html",
+		fmt.Sprintf("Output format (%s)", strings.Join(exportFormats, "|")))
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write to file instead of stdout")
+
+	GcpCmd.AddCommand(exportCmd)
+}
+
+// runExportCommand writes the catalogue in the selected format
+func runExportCommand(cmd *cobra.Command, args []string) error {
+	var write func(io.Writer, []catalogueEntry) error
+	switch strings.ToLower(exportFormat) {
+	case "bookmarks-html", "html", "bookmarks":
+		write = writeBookmarksHTML
+	case "markdown", "md":
+		write = writeMarkdown
+	case "csv":
+		write = writeCSV
+	case "json":
+		write = writeJSON
+	default:
+		return fmt.Errorf("unknown format '%s'. Valid: %s", exportFormat, strings.Join(exportFormats, ", "))
+	}
+
+	entries := buildCatalogue()
+
+	if exportOutput == "" {
+		return write(cmd.OutOrStdout(), entries)
+	}
+
+	f, err := os.Create(exportOutput)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	if err := write(f, entries); err != nil {
+		f.Close()
+		return err
+	}
+	// A full disk or NFS may only report a failed write on close
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s✓ Exported %d links to %s%s\n", colorGreen, len(entries), exportOutput, colorReset)
+	return nil
+}
+
+// buildCatalogue walks every project × environment × service from the config
+func buildCatalogue() []catalogueEntry {
+	var entries []catalogueEntry
+	for i := range config.Projects {
+		project := &config.Projects[i]
+		for _, env := range project.Environments {
+			for _, service := range availableServices(project, env) {
+				entries = append(entries, catalogueEntry{
+					Project:     project.Name,
+					ProjectID:   project.ID,
+					Environment: env,
+					Service:     service.Name,
+					URL:         buildURL(project, env, service),
+				})
+			}
+		}
+	}
+	return entries
+}
+
+// writeBookmarksHTML writes a Netscape bookmark file with a folder per project and environment
+func writeBookmarksHTML(w io.Writer, entries []catalogueEntry) error {
+	var b strings.Builder
+
+	b.WriteString("<!DOCTYPE NETSCAPE-Bookmark-file-1>\n")
+	b.WriteString("<!-- This is an automatically generated file. Generated by sun gcp export. -->\n")
+	b.WriteString("<META HTTP-EQUIV=\"Content-Type\" CONTENT=\"text/html; charset=UTF-8\">\n")
+	b.WriteString("<TITLE>Bookmarks</TITLE>\n")
+	b.WriteString("<H1>Bookmarks</H1>\n")
+	b.WriteString("<DL><p>\n")
+	b.WriteString("    <DT><H3>GCP Console</H3>\n")
+	b.WriteString("    <DL><p>\n")
+
+	project, env := "", ""
+	for i, e := range entries {
+		if i == 0 || e.Project != project {
+			if i > 0 {
+				b.WriteString("            </DL><p>\n")
+				b.WriteString("        </DL><p>\n")
+			}
+			project, env = e.Project, ""
+			fmt.Fprintf(&b, "        <DT><H3>%s</H3>\n", html.EscapeString(e.Project))
+			b.WriteString("        <DL><p>\n")
+		}
+		if e.Environment != env {
+			if env != "" {
+				b.WriteString("            </DL><p>\n")
+			}
+			env = e.Environment
+			fmt.Fprintf(&b, "            <DT><H3>%s</H3>\n", html.EscapeString(e.Environment))
+			b.WriteString("            <DL><p>\n")
+		}
+		fmt.Fprintf(&b, "                <DT><A HREF=\"%s\">%s</A>\n", html.EscapeString(e.URL), html.EscapeString(e.Service))
+	}
+	if len(entries) > 0 {
+		b.WriteString("            </DL><p>\n")
+		b.WriteString("        </DL><p>\n")
+	}
+
+	b.WriteString("    </DL><p>\n")
+	b.WriteString("</DL><p>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMarkdown writes a Markdown link table
+func writeMarkdown(w io.Writer, entries []catalogueEntry) error {
+	var b strings.Builder
+
+	b.WriteString("| Project | Environment | Service |\n")
+	b.WriteString("|---------|-------------|---------|\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "| %s | %s | [%s](%s) |\n",
+			escapeMarkdown(e.Project), escapeMarkdown(e.Environment), escapeMarkdown(e.Service), e.URL)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeMarkdown escapes characters that would break a Markdown table cell or link text
+func escapeMarkdown(s string) string {
+	replacer := strings.NewReplacer("|", "\\|", "[", "\\[", "]", "\\]")
+	return replacer.Replace(s)
+}
+
+// writeCSV writes the catalogue as CSV with a header row
+func writeCSV(w io.Writer, entries []catalogueEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"project", "project_id", "environment", "service", "url"}); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := cw.Write([]string{e.Project, e.ProjectID, e.Environment, e.Service, e.URL}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeJSON writes the catalogue as an indented JSON array
+func writeJSON(w io.Writer, entries []catalogueEntry) error {
+	if entries == nil {
+		entries = []catalogueEntry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(entries)
+}
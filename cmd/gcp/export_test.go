@@ -0,0 +1,49 @@
+package gcp
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExportCSVRoundTrip(t *testing.T) {
+	projects := []Project{
+		{Name: "AirAsia MOVE", ID: "airasia-move", Environments: []string{"prod", "staging"}},
+		{Name: `Ride, "beta"`, ID: "airasia-ride", Environments: []string{"dev"}},
+	}
+	useConfig(t, Config{
+		Projects: projects,
+		Services: []Service{
+			{Name: "Cloud Run", Path: "run"},
+			{Name: "Logs Explorer", Path: "logs/query"},
+		},
+	})
+
+	output := filepath.Join(t.TempDir(), "catalogue.csv")
+	savedFormat, savedOutput := exportFormat, exportOutput
+	exportFormat, exportOutput = "csv", output
+	t.Cleanup(func() { exportFormat, exportOutput = savedFormat, savedOutput })
+
+	if err := runExportCommand(exportCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, envs, err := parseImportCSV(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("ids = %q, want every row with its environment", ids)
+	}
+
+	var imported Config
+	applyImport(&imported, planImport(&imported, envs))
+	if !reflect.DeepEqual(imported.Projects, projects) {
+		t.Errorf("imported projects = %+v, want %+v", imported.Projects, projects)
+	}
+}
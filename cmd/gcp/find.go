@@ -0,0 +1,396 @@
+// cmd/gcp/find.go
+package gcp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/chzyer/readline"
+	"github.com/manifoldco/promptui"
+	"github.com/manifoldco/promptui/screenbuf"
+	"github.com/spf13/cobra"
+)
+
+// findResultsShown is the number of ranked results shown below the query
+const findResultsShown = 10
+
+// findCandidate is a single entry of the omnibox: a project × environment ×
+// service combination or a bookmark
+type findCandidate struct {
+	Label    string
+	Detail   string
+	Fields   []string // lower-cased text the query is matched against
+	Sel      *selection
+	Bookmark *Bookmark
+}
+
+// findCmd represents the gcp find command
+var findCmd = &cobra.Command{
+	Use:   "find [query...]",
+	Short: "Fuzzy-find a project/env/service or bookmark in one prompt",
+	Long: `Builds every valid project × environment × service combination, plus the
+bookmarks from the config, and ranks them as you type. Every word of the query
+must match the project, project ID, environment, service, category or bookmark
+name, exactly, as a prefix, as an abbreviation or fuzzily ("air prd logs").
+
+Use ↑/↓ to move the highlight, Enter to open it, and Esc to cancel. Pressing
+Enter without typing anything falls back to the step-by-step prompts.
+
+When stdin is not a terminal, the best match for the query is opened directly.`,
+	Example: `  sun gcp find
+  sun gcp find air prd logs
+  sun gcp -q`,
+	RunE: runFindCommand,
+}
+
+func init() {
+	addURLParamFlags(findCmd)
+	AddQRFlags(findCmd)
+
+	GcpCmd.AddCommand(findCmd)
+}
+
+// runFindCommand runs the omnibox with the arguments as the initial query
+func runFindCommand(cmd *cobra.Command, args []string) error {
+	if err := parseURLParamFlags(); err != nil {
+		return err
+	}
+	return runFind(strings.Join(args, " "))
+}
+
+// runFind ranks every candidate against the query and opens the chosen one
+func runFind(query string) error {
+	candidates := buildFindCandidates()
+	if len(candidates) == 0 {
+		return fmt.Errorf("nothing to find: no projects, services or bookmarks in %s", configFile)
+	}
+
+	var chosen *findCandidate
+	if isTerminal(os.Stdin) {
+		var err error
+		chosen, err = findPrompt(candidates, query)
+		if errors.Is(err, errCancelled) {
+			fmt.Printf("%sCancelled%s\n", colorDim, colorReset)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	} else {
+		if strings.TrimSpace(query) == "" {
+			return fmt.Errorf("a query is required when stdin is not a terminal")
+		}
+		ranked := rankCandidates(candidates, query)
+		if len(ranked) == 0 {
+			return fmt.Errorf("nothing matches '%s'", query)
+		}
+		chosen = ranked[0]
+	}
+
+	// Nothing typed: walk the usual three steps
+	if chosen == nil {
+		printBanner()
+		return launch(newWizard("", "", ""), viaInteractive)
+	}
+
+	if chosen.Bookmark != nil {
+		return openBookmark(chosen.Bookmark, viaFind)
+	}
+	return openSelection(chosen.Sel, viaFind)
+}
+
+// openBookmark opens a bookmark from the config and records it in the usage log
+func openBookmark(bookmark *Bookmark, via string) error {
+	fmt.Printf("\n%s%s✓ Bookmark%s %s\n", colorGreen, colorBold, colorReset, bookmark.Name)
+	fmt.Printf("\n%s🚀 Opening: %s%s\n\n", colorBlue, bookmark.URL, colorReset)
+
+	if err := printQR(bookmark.URL); err != nil {
+		return err
+	}
+	if err := openBrowser(bookmark.URL); err != nil {
+		return err
+	}
+
+	recordUsage(usageEntry{Bookmark: bookmark.Name, Via: via})
+	return nil
+}
+
+// buildFindCandidates lists every project × environment × available service, then the bookmarks
+func buildFindCandidates() []*findCandidate {
+	var candidates []*findCandidate
+	for i := range config.Projects {
+		project := &config.Projects[i]
+		for _, env := range project.Environments {
+			for _, service := range availableServices(project, env) {
+				candidates = append(candidates, &findCandidate{
+					Label:  fmt.Sprintf("%s / %s / %s", project.Name, env, service.Name),
+					Detail: service.Category,
+					Fields: lowerAll(project.Name, project.ID, env, service.Name, service.Category),
+					Sel:    &selection{Project: project, Env: env, Service: service},
+				})
+			}
+		}
+	}
+	for i := range config.Bookmarks {
+		bookmark := &config.Bookmarks[i]
+		candidates = append(candidates, &findCandidate{
+			Label:    "★ " + bookmark.Name,
+			Detail:   bookmark.URL,
+			Fields:   lowerAll(bookmark.Name, "bookmark"),
+			Bookmark: bookmark,
+		})
+	}
+	return candidates
+}
+
+// lowerAll lower-cases every non-empty string
+func lowerAll(items ...string) []string {
+	var lowered []string
+	for _, item := range items {
+		if item != "" {
+			lowered = append(lowered, strings.ToLower(item))
+		}
+	}
+	return lowered
+}
+
+// rankCandidates returns the candidates matching every word of the query,
+// best first; ties keep the config order
+func rankCandidates(candidates []*findCandidate, query string) []*findCandidate {
+	tokens := strings.Fields(strings.ToLower(query))
+	if len(tokens) == 0 {
+		return candidates
+	}
+
+	type scored struct {
+		candidate *findCandidate
+		score     int
+	}
+
+	var matches []scored
+	for _, c := range candidates {
+		total := 0
+		for _, token := range tokens {
+			best := 0
+			for _, field := range c.Fields {
+				if score := tokenScore(token, field); score > best {
+					best = score
+				}
+			}
+			if best == 0 {
+				total = 0
+				break
+			}
+			total += best
+		}
+		if total > 0 {
+			matches = append(matches, scored{c, total})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	ranked := make([]*findCandidate, len(matches))
+	for i, m := range matches {
+		ranked[i] = m.candidate
+	}
+	return ranked
+}
+
+// tokenScore scores how well a query word matches a field; 0 means no match.
+// Exact and prefix matches beat word prefixes, abbreviations and substrings,
+// which beat in-order character matches.
+func tokenScore(token, field string) int {
+	switch {
+	case field == token:
+		return 100
+	case strings.HasPrefix(field, token):
+		return 80
+	}
+
+	for _, word := range strings.FieldsFunc(field, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if strings.HasPrefix(word, token) {
+			return 60
+		}
+	}
+
+	if expansions, ok := serviceAbbreviations[token]; ok {
+		for _, expansion := range expansions {
+			if strings.Contains(field, expansion) {
+				return 50
+			}
+		}
+	}
+
+	if strings.Contains(field, token) {
+		return 40
+	}
+
+	// In-order characters, rewarding consecutive runs and a shared first
+	// letter ("prd" in "prod")
+	score, run, i := 0, 0, 0
+	query := []rune(token)
+	if strings.HasPrefix(field, string(query[0])) {
+		score += 10
+	}
+	for _, r := range field {
+		if i < len(query) && r == query[i] {
+			i++
+			run++
+			score += run
+		} else {
+			run = 0
+		}
+	}
+	if i < len(query) {
+		return 0
+	}
+	return min(1+score, 39)
+}
+
+// findPrompt shows the query line with the ranked candidates below it and
+// re-ranks on every key press. It returns nil when Enter is pressed without a query.
+func findPrompt(candidates []*findCandidate, query string) (*findCandidate, error) {
+	c := &readline.Config{
+		Stdin:  &escCanceller{},
+		Stdout: &bellSkipper{},
+	}
+	if err := c.Init(); err != nil {
+		return nil, err
+	}
+	c.Stdin = readline.NewCancelableStdin(c.Stdin)
+	c.HistoryLimit = -1
+	c.UniqueEditLine = true
+
+	rl, err := readline.NewEx(c)
+	if err != nil {
+		return nil, err
+	}
+
+	const (
+		hideCursor = "\033[?25l"
+		showCursor = "\033[?25h"
+		charCtrlU  = 21 // clears the query
+	)
+	rl.Write([]byte(hideCursor))
+	sb := screenbuf.New(rl)
+
+	input := []rune(query)
+	ranked := rankCandidates(candidates, query)
+	active := 0
+
+	render := func() {
+		width := readline.GetScreenWidth()
+		if width <= 0 {
+			width = 80
+		}
+
+		sb.WriteString(fmt.Sprintf("%s%s🔎 Find:%s %s%s▏%s", colorBold, colorBlue, colorReset, string(input), colorDim, colorReset))
+
+		// Keep the highlighted row inside the visible window
+		start := 0
+		if active >= findResultsShown {
+			start = active - findResultsShown + 1
+		}
+		end := min(start+findResultsShown, len(ranked))
+
+		for i := start; i < end; i++ {
+			sb.WriteString(formatCandidate(ranked[i], i == active, width))
+		}
+
+		switch {
+		case len(ranked) == 0:
+			sb.WriteString(fmt.Sprintf("%s  No matches%s", colorYellow, colorReset))
+		case len(ranked) > end:
+			sb.WriteString(fmt.Sprintf("%s  … %d more%s", colorDim, len(ranked)-end, colorReset))
+		}
+		sb.WriteString(fmt.Sprintf("%s  ↑/↓ move • enter open • esc cancel%s", colorDim, colorReset))
+		sb.Flush()
+	}
+
+	c.SetListener(func(line []rune, pos int, key rune) ([]rune, int, bool) {
+		switch {
+		case key == promptui.KeyEnter:
+			return nil, 0, true
+		case key == promptui.KeyNext:
+			if active < len(ranked)-1 {
+				active++
+			}
+		case key == promptui.KeyPrev:
+			if active > 0 {
+				active--
+			}
+		case key == promptui.KeyBackspace || key == promptui.KeyCtrlH:
+			if len(input) > 0 {
+				input = input[:len(input)-1]
+				ranked, active = rankCandidates(candidates, string(input)), 0
+			}
+		case key == charCtrlU:
+			input = nil
+			ranked, active = candidates, 0
+		case unicode.IsPrint(key):
+			input = append(input, line...)
+			ranked, active = rankCandidates(candidates, string(input)), 0
+		}
+
+		render()
+		return nil, 0, true
+	})
+
+	finish := func() {
+		sb.Reset()
+		sb.Clear()
+		sb.Flush()
+		rl.Write([]byte(showCursor))
+		rl.Close()
+	}
+
+	for {
+		if _, err := rl.Readline(); err != nil {
+			finish()
+			if errors.Is(err, readline.ErrInterrupt) || errors.Is(err, io.EOF) || err.Error() == "Interrupt" {
+				return nil, errCancelled
+			}
+			return nil, fmt.Errorf("find failed: %w", err)
+		}
+
+		if strings.TrimSpace(string(input)) == "" {
+			finish()
+			return nil, nil
+		}
+		if len(ranked) > 0 {
+			finish()
+			chosen := ranked[active]
+			fmt.Printf("%s✓ Found:%s %s\n", colorGreen, colorReset, chosen.Label)
+			return chosen, nil
+		}
+	}
+}
+
+// formatCandidate renders a result row, cut to the terminal width
+func formatCandidate(c *findCandidate, active bool, width int) string {
+	label := []rune(c.Label)
+	room := width - 3
+	if len(label) > room {
+		label = append(label[:max(room-1, 0)], '…')
+	}
+
+	detail := ""
+	if c.Detail != "" && len(label)+len([]rune(c.Detail))+2 <= room {
+		detail = fmt.Sprintf("  %s%s%s", colorDim, c.Detail, colorReset)
+	}
+
+	if active {
+		return fmt.Sprintf("%s▸ %s%s%s", colorBlue, colorBold, string(label), colorReset) + detail
+	}
+	return "  " + string(label) + detail
+}
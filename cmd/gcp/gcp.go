@@ -2,7 +2,7 @@
 package gcp
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,25 +11,71 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/itsiqbal/sun-cli/internal/qr"
+	"github.com/itsiqbal/sun-cli/internal/store"
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 )
 
 // Configuration structures
 type Project struct {
-	Name         string   `json:"name"`
-	ID           string   `json:"id"`
-	Environments []string `json:"environments"`
+	Name         string            `json:"name"`
+	ID           string            `json:"id"`
+	Environments []string          `json:"environments"`
+	Region       string            `json:"region,omitempty"`    // default for the {region} URL placeholder
+	Clusters     map[string]string `json:"clusters,omitempty"`  // environment → kubectl context or GKE cluster name
+	Variables    map[string]string `json:"variables,omitempty"` // extra variables for `sun gcp env`
+	// DefaultServices maps an environment to the service opened when only
+	// project and environment are given, or Enter is pressed in the picker
+	DefaultServices map[string]string `json:"defaultServices,omitempty"`
+	Metadata
+	EnvMetadata map[string]Metadata `json:"envMetadata,omitempty"` // per-environment overrides
+}
+
+// Metadata holds ownership and runbook details of a project or environment
+type Metadata struct {
+	Owners   []string          `json:"owners,omitempty"`
+	OnCall   string            `json:"onCall,omitempty"`
+	Slack    string            `json:"slack,omitempty"`
+	Repo     string            `json:"repo,omitempty"`
+	Runbooks []Link            `json:"runbooks,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Notes    string            `json:"notes,omitempty"`
+}
+
+type Link struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
 }
 
 type Service struct {
+	Name     string        `json:"name"`
+	Path     string        `json:"path"`
+	Category string        `json:"category,omitempty"` // e.g. Compute, Data, Observability, Security
+	Include  *ServiceScope `json:"include,omitempty"`  // only offer the service here
+	Exclude  *ServiceScope `json:"exclude,omitempty"`  // never offer the service here
+	Commands []string      `json:"commands,omitempty"` // gcloud/kubectl/bq templates for `sun gcp cli`
+	// Params maps URL query parameters to templates using the time range and
+	// location placeholders; parameters with an unset placeholder are left out
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// ServiceScope selects project environments by project name or ID and
+// environment name. An empty list matches everything.
+type ServiceScope struct {
+	Projects     []string `json:"projects,omitempty"`
+	Environments []string `json:"environments,omitempty"`
+}
+
+type Bookmark struct {
 	Name string `json:"name"`
-	Path string `json:"path"`
+	URL  string `json:"url"`
 }
 
 type Config struct {
-	Projects []Project `json:"projects"`
-	Services []Service `json:"services"`
+	Projects  []Project  `json:"projects"`
+	Services  []Service  `json:"services"`
+	Bookmarks []Bookmark `json:"bookmarks,omitempty"`
 }
 
 type CacheData struct {
@@ -45,11 +91,18 @@ var (
 	serviceFlag string
 	listFlag    bool
 	repeatFlag  bool
+	quickFlag   bool
+	labelFlags  []string
+	qrFlag      bool
+	qrPNGFlag   string
 
 	// Configuration
-	config    Config
-	configDir string
-	cacheFile string
+	config       Config
+	configDir    string
+	configFile   string
+	cacheFile    string
+	useStateFile string
+	usageFile    string
 )
 
 const (
@@ -89,7 +142,19 @@ Examples:
   gcp                          # Interactive mode
   gcp air prod k8s             # Direct mode with partial matches
   gcp --repeat                 # Use last selection
-  gcp --list                   # List available options`,
+  gcp air prod logs --qr       # Also show the URL as a QR code
+  gcp air prod logs --since 30m --region asia-southeast1
+  gcp -q                       # Fuzzy-find in one prompt ("air prd logs")
+  gcp --list                   # List available options
+  gcp export --format markdown # Export all console links
+  gcp import projects.json     # Import projects from gcloud output
+  gcp which <console-url>      # Resolve a console link to project/env/service
+  gcp use air prod             # Switch gcloud project and kubectl context
+  gcp env air prod             # Print shell exports for eval
+  gcp info air prod            # Show owners, on-call and runbooks
+  gcp stats --since 30d        # Show which pages are opened most
+  gcp cli air prod sql         # Print gcloud commands for a service
+  gcp -l --label team=payments # List projects by label`,
 	RunE: runGcpCommand,
 }
 
@@ -100,6 +165,10 @@ func init() {
 	GcpCmd.Flags().StringVarP(&serviceFlag, "service", "s", "", "Service name (partial match supported)")
 	GcpCmd.Flags().BoolVarP(&listFlag, "list", "l", false, "List available projects and services")
 	GcpCmd.Flags().BoolVarP(&repeatFlag, "repeat", "r", false, "Use last selection")
+	GcpCmd.Flags().BoolVarP(&quickFlag, "quick", "q", false, "Fuzzy-find a project/env/service in one prompt")
+	GcpCmd.Flags().StringSliceVar(&labelFlags, "label", nil, "Filter --list by label (key=value or key, repeatable)")
+	addURLParamFlags(GcpCmd)
+	AddQRFlags(GcpCmd)
 
 	// Initialize configuration paths
 	homeDir, err := os.UserHomeDir()
@@ -109,7 +178,10 @@ func init() {
 	}
 
 	configDir = filepath.Join(homeDir, ".config", "sun-cli")
+	configFile = filepath.Join(configDir, "gcp-config.json")
 	cacheFile = filepath.Join(configDir, "gcp-cache.json")
+	useStateFile = filepath.Join(configDir, "gcp-use.json")
+	usageFile = filepath.Join(configDir, "gcp-usage.jsonl")
 
 	// Ensure config directory exists
 	if err := os.MkdirAll(configDir, 0755); err != nil {
@@ -127,40 +199,52 @@ func init() {
 
 // loadConfig loads configuration from JSON file
 func loadConfig() error {
-	configFile := filepath.Join(configDir, "gcp-config.json")
-
 	// Check if config file exists
 	if _, err := os.Stat(configFile); os.IsNotExist(err) {
 		// Create default config file
-		defaultConfig := getDefaultConfig()
-		return saveConfig(configFile, defaultConfig)
+		config = getDefaultConfig()
+		return saveConfig(configFile, config)
 	}
 
-	// Read config file
-	data, err := os.ReadFile(configFile)
-	if err != nil {
+	// Read and parse config file; a corrupted file is moved aside
+	if err := store.ReadJSON(configFile, &config); err != nil {
+		var corrupt *store.CorruptError
+		if errors.As(err, &corrupt) {
+			return fmt.Errorf("config file is corrupted, kept a copy at %s: %w", corrupt.Backup, corrupt.Err)
+		}
 		return fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	// Parse JSON
-	if err := json.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("failed to parse config file: %w", err)
-	}
-
 	return nil
 }
 
-// saveConfig saves configuration to JSON file
+// saveConfig atomically saves configuration to JSON file
 func saveConfig(path string, cfg Config) error {
-	data, err := json.MarshalIndent(cfg, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
+	if err := store.WriteJSON(path, cfg, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
+	return nil
+}
+
+// updateConfig applies edit to the latest config file under its lock, so
+// concurrent runs cannot overwrite each other's changes, and makes the
+// result the current config
+func updateConfig(edit func(cfg *Config)) error {
+	var cfg Config
+	err := store.UpdateJSON(configFile, &cfg, 0644, func() error {
+		edit(&cfg)
+		return nil
+	})
+	if err != nil {
+		var corrupt *store.CorruptError
+		if errors.As(err, &corrupt) {
+			return fmt.Errorf("config file is corrupted, kept a copy at %s: %w", corrupt.Backup, corrupt.Err)
+		}
+		return fmt.Errorf("failed to update config file: %w", err)
 	}
 
+	config = cfg
 	return nil
 }
 
@@ -185,16 +269,67 @@ func getDefaultConfig() Config {
 			},
 		},
 		Services: []Service{
-			{Name: "Kubernetes Workloads", Path: "kubernetes/workload"},
-			{Name: "Cloud SQL (MySQL)", Path: "sql/instances"},
-			{Name: "Logs Explorer", Path: "logs/query"},
-			{Name: "Monitoring Dashboards", Path: "monitoring/dashboards"},
-			{Name: "Cloud Storage", Path: "storage/browser"},
-			{Name: "Cloud Run", Path: "run"},
-			{Name: "Cloud Functions", Path: "functions/list"},
-			{Name: "IAM & Admin", Path: "iam-admin/iam"},
-			{Name: "Compute Engine", Path: "compute/instances"},
-			{Name: "BigQuery", Path: "bigquery"},
+			{
+				Name:     "Kubernetes Workloads",
+				Path:     "kubernetes/workload",
+				Category: "Compute",
+				Commands: []string{"gcloud container clusters list --project {project}"},
+			},
+			{
+				Name:     "Cloud SQL (MySQL)",
+				Path:     "sql/instances",
+				Category: "Data",
+				Commands: []string{"gcloud sql instances list --project {project}"},
+			},
+			{
+				Name:     "Logs Explorer",
+				Path:     "logs/query;timeRange={range}",
+				Category: "Observability",
+				Commands: []string{"gcloud logging read --project {project} --freshness 1h --limit 50"},
+			},
+			{
+				Name:     "Monitoring Dashboards",
+				Path:     "monitoring/dashboards",
+				Category: "Observability",
+				Commands: []string{"gcloud monitoring dashboards list --project {project}"},
+				Params:   map[string]string{"startTime": "{start}", "endTime": "{end}"},
+			},
+			{
+				Name:     "Cloud Storage",
+				Path:     "storage/browser",
+				Category: "Data",
+				Commands: []string{"gcloud storage buckets list --project {project}"},
+			},
+			{
+				Name:     "Cloud Run",
+				Path:     "run",
+				Category: "Compute",
+				Commands: []string{"gcloud run services list --project {project}"},
+			},
+			{
+				Name:     "Cloud Functions",
+				Path:     "functions/list",
+				Category: "Compute",
+				Commands: []string{"gcloud functions list --project {project}"},
+			},
+			{
+				Name:     "IAM & Admin",
+				Path:     "iam-admin/iam",
+				Category: "Security",
+				Commands: []string{"gcloud projects get-iam-policy {project}"},
+			},
+			{
+				Name:     "Compute Engine",
+				Path:     "compute/instances",
+				Category: "Compute",
+				Commands: []string{"gcloud compute instances list --project {project}"},
+			},
+			{
+				Name:     "BigQuery",
+				Path:     "bigquery",
+				Category: "Data",
+				Commands: []string{"bq ls --project_id {project}"},
+			},
 		},
 	}
 }
@@ -206,11 +341,20 @@ func runGcpCommand(cmd *cobra.Command, args []string) error {
 		return listOptions()
 	}
 
+	if err := parseURLParamFlags(); err != nil {
+		return err
+	}
+
 	// Handle repeat flag
 	if repeatFlag {
 		return repeatLastSelection()
 	}
 
+	// Handle quick flag; arguments pre-fill the query
+	if quickFlag {
+		return runFind(strings.Join(args, " "))
+	}
+
 	// Print welcome banner
 	printBanner()
 
@@ -225,87 +369,69 @@ func runGcpCommand(cmd *cobra.Command, args []string) error {
 		serviceFlag = args[2]
 	}
 
-	// Selection loop with back navigation
-	var project *Project
-	var env string
-	var service *Service
-	var err error
+	w := newWizard(projectFlag, envFlag, serviceFlag)
+	return launch(w, viaFlags)
+}
 
-	// Step 1: Select project
-	for {
-		project, err = selectProject(projectFlag)
-		if err != nil {
-			fmt.Println("❌ Error selecting project:", err)
-			continue // retry selection
-		}
-		break // success → exit loop
+// launch runs the wizard, then opens and caches the selection. via records
+// how the page was reached; viaFlags becomes viaInteractive once a prompt is shown.
+func launch(w *wizard, via string) error {
+	sel, err := w.run()
+	if errors.Is(err, errCancelled) {
+		fmt.Printf("%sCancelled%s\n", colorDim, colorReset)
+		return nil
 	}
-
-	// Step 2: Select environment (with back to project)
-	for {
-		env, err = selectEnvironment(project, envFlag)
-		if err != nil {
-			if err.Error() == "go_back" {
-				// User wants to go back to project selection
-				projectFlag = "" // Reset to show interactive prompt
-				project, err = selectProject(projectFlag)
-				if err != nil {
-					return err
-				}
-				continue
-			}
-			return err
-		}
-		break
+	if err != nil {
+		return err
 	}
 
-	// Step 3: Select service (with back to environment)
-	for {
-		service, err = selectService(serviceFlag)
-		if err != nil {
-			if err.Error() == "go_back" {
-				// User wants to go back to environment selection
-				envFlag = "" // Reset to show interactive prompt
-				continue
-			}
-			return err
-		}
-		break
+	if via == viaFlags && w.prompted {
+		via = viaInteractive
 	}
+	return openSelection(sel, via)
+}
 
+// openSelection opens the console page of a selection, caches it and records it in the usage log
+func openSelection(sel *selection, via string) error {
 	// Build and open URL
-	url := buildURL(project, env, service)
-	printSummary(project, env, service, url)
+	url := buildURL(sel.Project, sel.Env, sel.Service)
+	printSummary(sel.Project, sel.Env, sel.Service, url)
+
+	if err := printQR(url); err != nil {
+		return err
+	}
 
 	if err := openBrowser(url); err != nil {
 		return err
 	}
 
 	// Cache selection
-	saveCache(project.Name, env, service.Name)
+	saveCache(sel.Project.Name, sel.Env, sel.Service.Name)
+	recordUsage(usageEntry{Project: sel.Project.Name, Env: sel.Env, Service: sel.Service.Name, Via: via})
 
 	return nil
 }
 
-// selectProject handles project selection with improved partial matching
-func selectProject(filter string) (*Project, error) {
+// selectProject handles project selection with improved partial matching.
+// current is the previous answer, used as the initial cursor position.
+func selectProject(filter, current string) (*Project, error) {
 	if filter != "" {
 		// Find matching project (case-insensitive, partial match)
 		matched := findMatchingProject(filter)
 		if matched == nil {
 			// Show available projects to help user
-			fmt.Printf("%sNo project matching '%s'. Available projects:%s\n", colorYellow, filter, colorReset)
+			fmt.Fprintf(wizardOut, "%sNo project matching '%s'. Available projects:%s\n", colorYellow, filter, colorReset)
 			for _, p := range config.Projects {
-				fmt.Printf("  • %s\n", p.Name)
+				fmt.Fprintf(wizardOut, "  • %s\n", p.Name)
 			}
 			return nil, fmt.Errorf("no project matching '%s'", filter)
 		}
-		fmt.Printf("%s✓ Matched project:%s %s\n", colorGreen, colorReset, matched.Name)
+		fmt.Fprintf(wizardOut, "%s✓ Matched project:%s %s\n", colorGreen, colorReset, matched.Name)
 		return matched, nil
 	}
 
 	// Interactive selection with fuzzy search
-	fmt.Printf("\n%s%s📁 Select a GCP Project:%s\n", colorBold, colorBlue, colorReset)
+	fmt.Fprintf(wizardOut, "\n%s%s📁 Select a GCP Project:%s\n", colorBold, colorBlue, colorReset)
 
 	projectNames := make([]string, len(config.Projects))
 	for i, p := range config.Projects {
@@ -337,9 +463,10 @@ func selectProject(filter string) (*Project, error) {
 		Items:             projectNames,
 		Size:              len(projectNames),
 		HideHelp:          false,
+		Stdin:             &escCanceller{},
 		Stdout:            &bellSkipper{},
-		CursorPos:         0,
-		StartInSearchMode: true,
+		CursorPos:         max(indexOf(projectNames, current), 0),
+		StartInSearchMode: current == "", // Search resets the cursor, so browse when revisiting
 		Searcher:          searcher,
 		Templates: &promptui.SelectTemplates{
 			Active:   "▸ {{ . | cyan }}",
@@ -351,34 +478,35 @@ func selectProject(filter string) (*Project, error) {
 
 	_, result, err := prompt.Run()
 	if err != nil {
-		return nil, fmt.Errorf("project selection cancelled: %w", err)
+		return nil, promptError("project", err)
 	}
 
 	return findProjectByName(result), nil
 }
 
-// selectEnvironment handles environment selection with validation
-func selectEnvironment(project *Project, filter string) (string, error) {
+// selectEnvironment handles environment selection with validation.
+// current is the previous answer, used as the initial cursor position.
+func selectEnvironment(project *Project, filter, current string) (string, error) {
 	if filter != "" {
 		// Validate environment (case-insensitive match)
 		for _, env := range project.Environments {
 			if strings.EqualFold(env, filter) {
-				fmt.Printf("%s✓ Matched environment:%s %s\n", colorGreen, colorReset, env)
+				fmt.Fprintf(wizardOut, "%s✓ Matched environment:%s %s\n", colorGreen, colorReset, env)
 				return env, nil
 			}
 		}
 		// Environment not found
-		fmt.Printf("%sInvalid environment '%s' for project '%s'. Available:%s\n",
+		fmt.Fprintf(wizardOut, "%sInvalid environment '%s' for project '%s'. Available:%s\n",
 			colorYellow, filter, project.Name, colorReset)
 		for _, env := range project.Environments {
-			fmt.Printf("  • %s\n", env)
+			fmt.Fprintf(wizardOut, "  • %s\n", env)
 		}
 		return "", fmt.Errorf("invalid environment '%s' for project '%s'. Valid: %v",
 			filter, project.Name, project.Environments)
 	}
 
 	// Interactive selection with fuzzy search and back option
-	fmt.Printf("\n%s%s🌎 Select an Environment:%s\n", colorBold, colorBlue, colorReset)
+	fmt.Fprintf(wizardOut, "\n%s%s🌎 Select an Environment:%s\n", colorBold, colorBlue, colorReset)
 
 	// Add "← Go Back" option
 	envOptions := make([]string, len(project.Environments)+1)
@@ -426,160 +554,168 @@ func selectEnvironment(project *Project, filter string) (string, error) {
 		Items:             envOptions,
 		Size:              len(envOptions),
 		HideHelp:          false,
+		Stdin:             &escCanceller{},
 		Stdout:            &bellSkipper{},
-		CursorPos:         1, // Start on first real environment, not back option
-		StartInSearchMode: len(project.Environments) > 4,
+		CursorPos:         max(indexOf(envOptions, current), 1), // Start on a real environment, not back option
+		StartInSearchMode: len(project.Environments) > 4 && current == "",
 		Searcher:          searcher,
 		Templates:         templates,
 	}
 
 	_, result, err := prompt.Run()
 	if err != nil {
-		return "", fmt.Errorf("environment selection cancelled: %w", err)
+		return "", promptError("environment", err)
 	}
 
 	// Check if user selected go back
 	if result == "← Go Back" {
-		return "", fmt.Errorf("go_back")
+		return "", errGoBack
 	}
 
 	return result, nil
 }
 
-// selectService handles service selection with improved partial matching
-func selectService(filter string) (*Service, error) {
+// selectService handles service selection with improved partial matching,
+// offering only the services available in the project environment.
+// current is the previous answer, used as the initial cursor position.
+func selectService(project *Project, env, filter, current string) (*Service, error) {
+	services := availableServices(project, env)
+
+	// A category prefix ("obs:", "data:logs") narrows the services first
+	if category, rest, ok := splitCategoryFilter(filter); ok {
+		services = servicesInCategory(services, category)
+		if len(services) == 0 {
+			return nil, fmt.Errorf("no service in a category matching '%s' in %s/%s", category, project.Name, env)
+		}
+		filter = rest
+	}
+
 	if filter != "" {
 		// Find matching service (case-insensitive, partial match)
-		matched := findMatchingService(filter)
+		matched := findMatchingService(services, filter)
 		if matched == nil {
 			// Show available services to help user
-			fmt.Printf("%sNo service matching '%s'. Available services:%s\n", colorYellow, filter, colorReset)
-			for _, s := range config.Services {
-				fmt.Printf("  • %s\n", s.Name)
+			if other := findMatchingService(allServices(), filter); other != nil {
+				fmt.Fprintf(wizardOut, "%s%s is not available in %s/%s. Available services:%s\n",
+					colorYellow, other.Name, project.Name, env, colorReset)
+			} else {
+				fmt.Fprintf(wizardOut, "%sNo service matching '%s'. Available services:%s\n", colorYellow, filter, colorReset)
 			}
-			return nil, fmt.Errorf("no service matching '%s'", filter)
+			for _, s := range services {
+				fmt.Fprintf(wizardOut, "  • %s\n", s.Name)
+			}
+			return nil, fmt.Errorf("no service matching '%s' in %s/%s", filter, project.Name, env)
 		}
-		fmt.Printf("%s✓ Matched service:%s %s\n", colorGreen, colorReset, matched.Name)
+		fmt.Fprintf(wizardOut, "%s✓ Matched service:%s %s\n", colorGreen, colorReset, matched.Name)
 		return matched, nil
 	}
 
 	// Interactive selection with fuzzy search and back option
-	fmt.Printf("\n%s%s🧩 Select a Service:%s\n", colorBold, colorBlue, colorReset)
-
-	serviceNames := make([]string, len(config.Services))
-	for i, s := range config.Services {
-		serviceNames[i] = s.Name
+	defaultService := findDefaultService(project, env)
+	if defaultService != nil && current == "" {
+		fmt.Fprintf(wizardOut, "\n%s%s🧩 Select a Service %s(enter for %s)%s\n",
+			colorBold, colorBlue, colorDim, defaultService.Name, colorReset)
+	} else {
+		fmt.Fprintf(wizardOut, "\n%s%s🧩 Select a Service:%s\n", colorBold, colorBlue, colorReset)
 	}
-	sort.Strings(serviceNames)
 
-	// Add "← Go Back" option
-	serviceOptions := make([]string, len(serviceNames)+1)
-	serviceOptions[0] = "← Go Back"
-	copy(serviceOptions[1:], serviceNames)
+	// Build picker rows: the default service pinned above "← Go Back" so
+	// Enter picks it even after the search resets the cursor to the top,
+	// then the services grouped under their category headers
+	options := buildServiceOptions(services, defaultService)
 
 	searcher := func(input string, index int) bool {
-		// Don't filter the back option
-		if index == 0 {
-			return strings.Contains(strings.ToLower("back"), strings.ToLower(input))
-		}
-
-		service := serviceOptions[index]
-		name := strings.ReplaceAll(strings.ToLower(service), " ", "")
-		input = strings.ReplaceAll(strings.ToLower(input), " ", "")
-
-		// Fuzzy search: check if all characters in input appear in order
-		if input == "" {
-			return true
-		}
-
-		// Also check for common abbreviations
-		abbreviations := map[string][]string{
-			"k8s":     {"kubernetes"},
-			"gke":     {"kubernetes"},
-			"sql":     {"sql", "database"},
-			"db":      {"sql", "database"},
-			"storage": {"storage", "gcs"},
-			"gcs":     {"storage"},
-			"logs":    {"logs", "explorer"},
-			"iam":     {"iam", "admin"},
-			"compute": {"compute", "engine", "vm"},
-			"vm":      {"compute", "engine"},
-			"bq":      {"bigquery"},
-			"pubsub":  {"pub", "sub"},
-			"run":     {"run"},
-			"fn":      {"functions"},
-			"lambda":  {"functions"},
-		}
-
-		// Check abbreviation match
-		if matches, ok := abbreviations[input]; ok {
-			for _, match := range matches {
-				if strings.Contains(name, match) {
-					return true
-				}
-			}
-		}
-
-		// Fuzzy character matching
-		inputIdx := 0
-		for _, char := range name {
-			if inputIdx < len(input) && char == rune(input[inputIdx]) {
-				inputIdx++
-			}
-		}
-		return inputIdx == len(input)
+		return options.matches(index, input)
 	}
 
 	templates := &promptui.SelectTemplates{
-		Active:   "▸ {{ . | cyan }}",
-		Inactive: "  {{ . }}",
-		Selected: "{{ \"✓\" | green }} {{ . | green }}",
-		Help:     "{{ \"Type to search (e.g., k8s, sql, logs)\" | faint }} {{ \"[↑↓ to move, enter to select, / to toggle search]\" | faint }}",
+		Active:   `{{if .Header}}  {{ .Name | faint }}{{else if .Back}}▸ {{ .Name | yellow }}{{else}}▸ {{ .Name | cyan }}{{end}}`,
+		Inactive: `{{if .Header}}  {{ .Name | bold }}{{else if .Back}}  {{ .Name | faint }}{{else}}  {{ .Name }}{{end}}`,
+		Selected: `{{if .Header}}{{ .Name | faint }}{{else}}{{ "✓" | green }} {{ .Name | green }}{{end}}`,
+		Help:     "{{ \"Type to search (e.g., k8s, sql, obs:logs)\" | faint }} {{ \"[↑↓ to move, enter to select, / to toggle search, esc to cancel]\" | faint }}",
+		FuncMap:  promptui.FuncMap,
 	}
 
-	// Customize template for back option
-	templates.FuncMap = promptui.FuncMap
-	templates.Active = `{{if eq . "← Go Back"}}▸ {{ . | yellow }}{{else}}▸ {{ . | cyan }}{{end}}`
-	templates.Inactive = `{{if eq . "← Go Back"}}  {{ . | faint }}{{else}}  {{ . }}{{end}}`
-
 	prompt := promptui.Select{
 		Label:             "Service",
-		Items:             serviceOptions,
+		Items:             options,
 		Size:              10,
 		HideHelp:          false,
+		Stdin:             &escCanceller{},
 		Stdout:            &bellSkipper{},
-		CursorPos:         1, // Start on first real service, not back option
-		StartInSearchMode: true,
+		StartInSearchMode: current == "",
 		Searcher:          searcher,
 		Templates:         templates,
 	}
 
-	_, result, err := prompt.Run()
-	if err != nil {
-		return nil, fmt.Errorf("service selection cancelled: %w", err)
-	}
+	cursor := options.cursor(current)
+	for {
+		index, _, err := prompt.RunCursorAt(cursor, 0)
+		if err != nil {
+			return nil, promptError("service", err)
+		}
 
-	// Check if user selected go back
-	if result == "← Go Back" {
-		return nil, fmt.Errorf("go_back")
+		option := options[index]
+		switch {
+		case option.Back:
+			return nil, errGoBack
+		case option.Header:
+			// Headers are not selectable; browse from the first service below
+			cursor = index + 1
+			prompt.StartInSearchMode = false
+			continue
+		}
+		return option.Service, nil
 	}
-
-	return findServiceByName(result), nil
 }
 
 // buildURL constructs the GCP Console URL
 func buildURL(project *Project, env string, service *Service) string {
 	baseURL := "https://console.cloud.google.com"
-	url := fmt.Sprintf("%s/%s?project=%s-%s", baseURL, service.Path, project.ID, env)
+	vars := urlVariables(project)
+	vars["project"] = projectID(project, env)
+	vars["id"] = project.ID
+	vars["name"] = project.Name
+	vars["env"] = env
+	servicePath := service.Path
+	// Logs Explorer takes the query as a matrix parameter, also when the
+	// configured path predates the {query} placeholder
+	if isLogsExplorer(service) && !strings.Contains(servicePath, "{query}") {
+		servicePath += ";query={query}"
+	}
+	path := resolveURLPath(servicePath, vars)
+	url := fmt.Sprintf("%s/%s?project=%s", baseURL, path, projectID(project, env))
 
 	// Add environment parameter for services that support it
 	if needsEnvParam(service.Path) {
 		url = fmt.Sprintf("%s&environment=%s", url, env)
 	}
 
+	// Add time range and location parameters the service declares
+	url += resolveURLQuery(service.Params, vars)
+
 	return url
 }
 
+// projectID returns the GCP project ID for a project environment
+func projectID(project *Project, env string) string {
+	return fmt.Sprintf("%s-%s", project.ID, env)
+}
+
+// resolvePlaceholders replaces {project}, {id}, {name} and {env} in s
+func resolvePlaceholders(s string, project *Project, env string) string {
+	if !strings.Contains(s, "{") {
+		return s
+	}
+	replacer := strings.NewReplacer(
+		"{project}", projectID(project, env),
+		"{id}", project.ID,
+		"{name}", project.Name,
+		"{env}", env,
+	)
+	return replacer.Replace(s)
+}
+
 // needsEnvParam checks if service needs environment parameter
 func needsEnvParam(servicePath string) bool {
 	envServices := []string{"kubernetes/", "run", "functions/"}
@@ -654,27 +790,27 @@ func findMatchingProject(filter string) *Project {
 }
 
 // findMatchingService finds a service by partial, case-insensitive name match
-func findMatchingService(filter string) *Service {
+func findMatchingService(services []*Service, filter string) *Service {
 	filter = strings.ToLower(strings.TrimSpace(filter))
 
 	// First pass: exact match (case-insensitive)
-	for i := range config.Services {
-		if strings.EqualFold(config.Services[i].Name, filter) {
-			return &config.Services[i]
+	for _, service := range services {
+		if strings.EqualFold(service.Name, filter) {
+			return service
 		}
 	}
 
 	// Second pass: starts with filter
-	for i := range config.Services {
-		if strings.HasPrefix(strings.ToLower(config.Services[i].Name), filter) {
-			return &config.Services[i]
+	for _, service := range services {
+		if strings.HasPrefix(strings.ToLower(service.Name), filter) {
+			return service
 		}
 	}
 
 	// Third pass: contains filter anywhere
-	for i := range config.Services {
-		if strings.Contains(strings.ToLower(config.Services[i].Name), filter) {
-			return &config.Services[i]
+	for _, service := range services {
+		if strings.Contains(strings.ToLower(service.Name), filter) {
+			return service
 		}
 	}
 
@@ -699,19 +835,19 @@ func findMatchingService(filter string) *Service {
 
 	// Check if filter is a known abbreviation
 	if expanded, ok := abbreviations[filter]; ok {
-		for i := range config.Services {
-			if strings.Contains(strings.ToLower(config.Services[i].Name), expanded) {
-				return &config.Services[i]
+		for _, service := range services {
+			if strings.Contains(strings.ToLower(service.Name), expanded) {
+				return service
 			}
 		}
 	}
 
 	// Check individual words
-	for i := range config.Services {
-		words := strings.Fields(strings.ToLower(config.Services[i].Name))
+	for _, service := range services {
+		words := strings.Fields(strings.ToLower(service.Name))
 		for _, word := range words {
 			if strings.HasPrefix(word, filter) {
-				return &config.Services[i]
+				return service
 			}
 		}
 	}
@@ -747,25 +883,15 @@ func saveCache(project, env, service string) {
 		Service: service,
 	}
 
-	data, err := json.MarshalIndent(cache, "", "  ")
-	if err != nil {
-		return
-	}
-
-	if err := os.WriteFile(cacheFile, data, 0644); err != nil {
+	if err := store.WriteJSON(cacheFile, cache, 0644); err != nil {
 		fmt.Printf("⚠️ Could not write cache file: %v\n", err)
 	}
 }
 
 // loadCache loads cached selection
 func loadCache() (*CacheData, error) {
-	data, err := os.ReadFile(cacheFile)
-	if err != nil {
-		return nil, err
-	}
-
 	var cache CacheData
-	if err := json.Unmarshal(data, &cache); err != nil {
+	if err := store.ReadJSON(cacheFile, &cache); err != nil {
 		return nil, err
 	}
 
@@ -781,44 +907,10 @@ func repeatLastSelection() error {
 
 	fmt.Printf("%s🔄 Using last selection...%s\n", colorYellow, colorReset)
 
-	// Set flags from cache
-	projectFlag = cache.Project
-	envFlag = cache.Env
-	serviceFlag = cache.Service
-
-	// Execute the main logic directly instead of calling runGcpCommand
+	// Execute the main logic with the cached answers pre-filled
 	printBanner()
 
-	// Select project
-	project, err := selectProject(projectFlag)
-	if err != nil {
-		return err
-	}
-
-	// Select environment
-	env, err := selectEnvironment(project, envFlag)
-	if err != nil {
-		return err
-	}
-
-	// Select service
-	service, err := selectService(serviceFlag)
-	if err != nil {
-		return err
-	}
-
-	// Build and open URL
-	url := buildURL(project, env, service)
-	printSummary(project, env, service, url)
-
-	if err := openBrowser(url); err != nil {
-		return err
-	}
-
-	// Cache selection (already cached but update timestamp)
-	saveCache(project.Name, env, service.Name)
-
-	return nil
+	return launch(newWizard(cache.Project, cache.Env, cache.Service), viaRepeat)
 }
 
 // listOptions lists all available projects and services
@@ -827,18 +919,45 @@ func listOptions() error {
 
 	// List projects
 	fmt.Printf("%sProjects:%s\n", colorBold, colorReset)
-	for _, p := range config.Projects {
+	for i := range config.Projects {
+		p := &config.Projects[i]
+		envs := p.Environments
+		if len(labelFlags) > 0 {
+			if envs = matchingEnvironments(p, labelFlags); len(envs) == 0 {
+				continue
+			}
+		}
 		fmt.Printf("  • %s → %s\n", p.Name, p.ID)
-		fmt.Printf("    %sEnvironments: %v%s\n", colorDim, p.Environments, colorReset)
+		fmt.Printf("    %sEnvironments: %v%s\n", colorDim, envs, colorReset)
+		if len(p.Labels) > 0 {
+			fmt.Printf("    %sLabels: %s%s\n", colorDim, formatLabels(p.Labels), colorReset)
+		}
 	}
 
-	// List services
+	// List services, grouped by category
 	fmt.Printf("\n%sServices:%s\n", colorBold, colorReset)
-	for _, s := range config.Services {
-		fmt.Printf("  • %s → %s\n", s.Name, s.Path)
+	groups := groupByCategory(allServices())
+	grouped := len(groups) > 1 || (len(groups) == 1 && groups[0].Category != otherCategory)
+	for _, g := range groups {
+		indent := "  "
+		if grouped {
+			fmt.Printf("  %s%s%s\n", colorDim, g.Category, colorReset)
+			indent = "    "
+		}
+		for _, s := range g.Services {
+			fmt.Printf("%s• %s → %s\n", indent, s.Name, s.Path)
+		}
+	}
+
+	// List bookmarks
+	if len(config.Bookmarks) > 0 {
+		fmt.Printf("\n%sBookmarks:%s\n", colorBold, colorReset)
+		for _, b := range config.Bookmarks {
+			fmt.Printf("  • %s → %s\n", b.Name, b.URL)
+		}
 	}
 
-	fmt.Printf("\n%sConfig location: %s%s\n", colorDim, filepath.Join(configDir, "gcp-config.json"), colorReset)
+	fmt.Printf("\n%sConfig location: %s%s\n", colorDim, configFile, colorReset)
 	fmt.Printf("\n%sTip: Use partial names like 'air' for AirAsia or 'k8s' for Kubernetes%s\n",
 		colorDim, colorReset)
 
@@ -858,5 +977,56 @@ func printSummary(project *Project, env string, service *Service, url string) {
 	fmt.Printf("%s  Project:     %s%s %s(%s)%s\n", colorDim, colorReset, project.Name, colorDim, project.ID, colorReset)
 	fmt.Printf("%s  Environment: %s%s\n", colorDim, colorReset, env)
 	fmt.Printf("%s  Service:     %s%s\n", colorDim, colorReset, service.Name)
+	// Only show the time range and region when the page takes them
+	if r := describeTimeRange(); r != "" && usesVariable(service, "start", "end", "duration", "range") {
+		fmt.Printf("%s  Time range:  %s%s\n", colorDim, colorReset, r)
+	}
+	if region := urlVariables(project)["region"]; region != "" && usesVariable(service, "region", "zone") {
+		fmt.Printf("%s  Region:      %s%s\n", colorDim, colorReset, region)
+	}
+	if logQuery != "" && isLogsExplorer(service) {
+		fmt.Printf("%s  Log filter:  %s%s\n", colorDim, colorReset, logQuery)
+	}
 	fmt.Printf("\n%s🚀 Opening: %s%s\n\n", colorBlue, url, colorReset)
 }
+
+// AddQRFlags registers --qr and --qr-png on a command that opens a console
+// page, such as sun ai open
+func AddQRFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&qrFlag, "qr", false, "Also show the console URL as a QR code in the terminal")
+	cmd.Flags().StringVar(&qrPNGFlag, "qr-png", "", "Also save the console URL as a QR code PNG to this file")
+}
+
+// printQR shows url as a QR code with --qr and saves it as a PNG with --qr-png
+func printQR(url string) error {
+	if !qrFlag && qrPNGFlag == "" {
+		return nil
+	}
+
+	code, err := qr.Encode(url, qr.Medium)
+	if err != nil {
+		return fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	if qrFlag {
+		if err := code.WriteTerminal(os.Stdout); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
+	if qrPNGFlag != "" {
+		f, err := os.Create(qrPNGFlag)
+		if err != nil {
+			return fmt.Errorf("failed to create QR code file: %w", err)
+		}
+		defer f.Close()
+
+		if err := code.WritePNG(f, 8); err != nil {
+			return fmt.Errorf("failed to write QR code: %w", err)
+		}
+		fmt.Printf("%s✓ Saved QR code to %s%s\n\n", colorGreen, qrPNGFlag, colorReset)
+	}
+
+	return nil
+}
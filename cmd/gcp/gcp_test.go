@@ -0,0 +1,14 @@
+package gcp
+
+import "testing"
+
+func TestBuildURLEscapesPlaceholders(t *testing.T) {
+	project := &Project{Name: "Air Asia #1", ID: "air"}
+	service := &Service{Name: "Dashboards", Path: "monitoring/dashboards/{name}/{env}"}
+
+	got := buildURL(project, "prod", service)
+	want := "https://console.cloud.google.com/monitoring/dashboards/Air%20Asia%20%231/prod?project=air-prod"
+	if got != want {
+		t.Errorf("buildURL = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,374 @@
+// cmd/gcp/import.go
+package gcp
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// gcloudProjectEntry is the part of `gcloud projects list --format=json` output we use
+type gcloudProjectEntry struct {
+	ProjectID string `json:"projectId"`
+}
+
+// importedEnv is a project environment read from the input
+type importedEnv struct {
+	Name string // project name, as used in the config
+	ID   string // project ID without the environment suffix
+	Env  string
+}
+
+// importChange is what merging the input does to one config project
+type importChange struct {
+	Project  string
+	ID       string
+	Existing int // index in config.Projects, or -1 for a new project
+	AddEnvs  []string
+}
+
+var (
+	// Import flags
+	importFormat  string
+	importPattern string
+	importEnvs    []string
+	importYes     bool
+	importDryRun  bool
+)
+
+// importCmd represents the gcp import command
+var importCmd = &cobra.Command{
+	Use:   "import [file|-]",
+	Short: "Import projects from gcloud JSON output or a CSV file",
+	Long: `Reads projects from "gcloud projects list --format=json" output or a CSV file
+(a path, or stdin when the file is "-" or omitted), groups them into projects
+with environments, shows what would change in the config and merges on
+confirmation.
+
+Project IDs are split into a name and an environment with --pattern, which
+uses the {name} and {env} placeholders; the environment cannot contain "-".
+As the launcher builds project IDs as <id>-<env>, {env} must come last:
+
+  --pattern "{name}-{env}"        air-move-prod  → air-move / prod
+  --pattern "acme-{name}-{env}"   acme-pay-dev   → pay / dev (ID acme-pay)
+
+CSV files need a header with a project ID column (projectId, project_id or
+id). With an env (or environment) column as well, that column is used as the
+environment, the ID as the project ID and the name column, if any, as the
+project name, and --pattern is not needed.`,
+	Example: `  gcloud projects list --format=json | sun gcp import
+  sun gcp import projects.json --pattern "acme-{name}-{env}" --envs prod,staging,dev
+  sun gcp import projects.csv --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runImportCommand,
+}
+
+func init() {
+	importCmd.Flags().StringVarP(&importFormat, "format", "f", "auto", "Input format (auto|json|csv)")
+	importCmd.Flags().StringVar(&importPattern, "pattern", "{name}-{env}", "Project ID naming pattern with {name} and {env}")
+	importCmd.Flags().StringSliceVar(&importEnvs, "envs", nil, "Only accept these environment names (comma-separated)")
+	importCmd.Flags().BoolVarP(&importYes, "yes", "y", false, "Merge without asking for confirmation")
+	importCmd.Flags().BoolVarP(&importDryRun, "dry-run", "n", false, "Show the changes without saving them")
+
+	GcpCmd.AddCommand(importCmd)
+}
+
+// runImportCommand reads the input, shows the diff and merges it into the config
+func runImportCommand(cmd *cobra.Command, args []string) error {
+	source := "-"
+	if len(args) > 0 {
+		source = args[0]
+	}
+
+	var data []byte
+	var err error
+	if source == "-" {
+		if isTerminal(os.Stdin) {
+			return fmt.Errorf("no input. Pipe gcloud output in or pass a file, e.g. gcloud projects list --format=json | sun gcp import")
+		}
+		data, err = io.ReadAll(os.Stdin)
+		source = "stdin"
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+
+	envs, skipped, err := parseImport(data)
+	if err != nil {
+		return err
+	}
+
+	changes := planImport(&config, envs)
+
+	fmt.Printf("\n%s%s📥 Import from %s%s %s(%d project environments read)%s\n",
+		colorBold, colorBlue, source, colorReset, colorDim, len(envs)+len(skipped), colorReset)
+	printImportChanges(changes, skipped)
+
+	if len(changes) == 0 || importDryRun {
+		return nil
+	}
+
+	// Confirming needs the terminal, which stdin may not be
+	if !importYes {
+		if !isTerminal(os.Stdin) {
+			return fmt.Errorf("input was read from stdin; pass --yes to merge without a prompt")
+		}
+		if !confirm("Merge into " + configFile) {
+			fmt.Printf("%sCancelled%s\n", colorDim, colorReset)
+			return nil
+		}
+	}
+
+	// Plan again against the latest file, in case it changed since
+	err = updateConfig(func(cfg *Config) {
+		applyImport(cfg, planImport(cfg, envs))
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s✓ Updated %s%s\n", colorGreen, configFile, colorReset)
+	return nil
+}
+
+// parseImport reads project environments from gcloud JSON or CSV. IDs that
+// do not fit the pattern or the allowed environments are returned as skipped.
+func parseImport(data []byte) ([]importedEnv, []string, error) {
+	format := strings.ToLower(importFormat)
+	if format == "auto" {
+		format = "csv"
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+			format = "json"
+		}
+	}
+
+	var ids []string
+	var direct []importedEnv
+	switch format {
+	case "json":
+		var projects []gcloudProjectEntry
+		if err := json.Unmarshal(data, &projects); err != nil {
+			return nil, nil, fmt.Errorf("invalid gcloud JSON: %w", err)
+		}
+		for _, p := range projects {
+			ids = append(ids, p.ProjectID)
+		}
+	case "csv":
+		var err error
+		ids, direct, err = parseImportCSV(data)
+		if err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown format '%s'. Valid: auto, json, csv", importFormat)
+	}
+
+	var envs []importedEnv
+	var skipped []string
+	for _, env := range direct {
+		if allowedImportEnv(env.Env) {
+			envs = append(envs, env)
+		} else {
+			skipped = append(skipped, env.ID+"-"+env.Env)
+		}
+	}
+
+	if len(ids) > 0 {
+		re, err := patternRegexp(importPattern)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, id := range ids {
+			env, ok := splitProjectID(re, id)
+			if !ok || !allowedImportEnv(env.Env) {
+				skipped = append(skipped, id)
+				continue
+			}
+			envs = append(envs, env)
+		}
+	}
+
+	return envs, skipped, nil
+}
+
+// parseImportCSV returns the project IDs of a CSV file, or the project
+// environments directly when the file has an environment column
+func parseImportCSV(data []byte) ([]string, []importedEnv, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("empty CSV")
+	}
+
+	idCol, nameCol, envCol := -1, -1, -1
+	for i, column := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case "projectid", "project_id", "id":
+			idCol = i
+		case "name", "project", "project_name":
+			nameCol = i
+		case "env", "environment":
+			envCol = i
+		}
+	}
+	if idCol < 0 {
+		return nil, nil, fmt.Errorf("CSV header needs a project ID column (projectId, project_id or id)")
+	}
+
+	field := func(record []string, col int) string {
+		if col < 0 || col >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[col])
+	}
+
+	var ids []string
+	var envs []importedEnv
+	for _, record := range records[1:] {
+		id := field(record, idCol)
+		if id == "" {
+			continue
+		}
+		if envCol < 0 {
+			ids = append(ids, id)
+			continue
+		}
+		// IDs are stored without the environment suffix, as splitProjectID does
+		env := field(record, envCol)
+		if suffix := "-" + env; env != "" && len(id) > len(suffix) && strings.EqualFold(id[len(id)-len(suffix):], suffix) {
+			id = id[:len(id)-len(suffix)]
+		}
+		name := field(record, nameCol)
+		if name == "" {
+			name = id
+		}
+		envs = append(envs, importedEnv{Name: name, ID: id, Env: env})
+	}
+	return ids, envs, nil
+}
+
+// patternRegexp turns a naming pattern into a regular expression capturing
+// the name and environment
+func patternRegexp(pattern string) (*regexp.Regexp, error) {
+	if strings.Count(pattern, "{name}") != 1 || strings.Count(pattern, "{env}") != 1 {
+		return nil, fmt.Errorf("pattern '%s' needs {name} and {env} exactly once", pattern)
+	}
+	if !strings.HasSuffix(pattern, "-{env}") {
+		return nil, fmt.Errorf("pattern '%s' must end with -{env}: project IDs are built as <id>-<env>", pattern)
+	}
+
+	// Project IDs are lowercase and splitProjectID lowercases what it matches
+	expr := regexp.QuoteMeta(strings.ToLower(pattern))
+	expr = strings.Replace(expr, regexp.QuoteMeta("{name}"), "(?P<name>.+?)", 1)
+	expr = strings.Replace(expr, regexp.QuoteMeta("{env}"), "(?P<env>[a-z0-9]+)", 1)
+	return regexp.Compile("^" + expr + "$")
+}
+
+// splitProjectID splits a full project ID into project and environment
+func splitProjectID(re *regexp.Regexp, id string) (importedEnv, bool) {
+	m := re.FindStringSubmatch(strings.ToLower(strings.TrimSpace(id)))
+	if m == nil {
+		return importedEnv{}, false
+	}
+	name := m[re.SubexpIndex("name")]
+	env := m[re.SubexpIndex("env")]
+	return importedEnv{
+		Name: name,
+		ID:   strings.TrimSuffix(strings.ToLower(id), "-"+env),
+		Env:  env,
+	}, true
+}
+
+// allowedImportEnv checks an environment against --envs
+func allowedImportEnv(env string) bool {
+	return env != "" && (len(importEnvs) == 0 || containsFold(importEnvs, env))
+}
+
+// planImport compares the imported environments with the config. Existing
+// projects are matched by ID; only missing environments are added.
+func planImport(cfg *Config, envs []importedEnv) []importChange {
+	var changes []importChange
+	index := map[string]int{}
+
+	for _, e := range envs {
+		i, ok := index[strings.ToLower(e.ID)]
+		if !ok {
+			change := importChange{Project: e.Name, ID: e.ID, Existing: -1}
+			for j, p := range cfg.Projects {
+				if strings.EqualFold(p.ID, e.ID) {
+					change.Project, change.Existing = p.Name, j
+					break
+				}
+			}
+			i = len(changes)
+			index[strings.ToLower(e.ID)] = i
+			changes = append(changes, change)
+		}
+
+		c := &changes[i]
+		if containsFold(c.AddEnvs, e.Env) {
+			continue
+		}
+		if c.Existing >= 0 && containsFold(cfg.Projects[c.Existing].Environments, e.Env) {
+			continue
+		}
+		c.AddEnvs = append(c.AddEnvs, e.Env)
+	}
+
+	// Drop existing projects that gain nothing
+	var planned []importChange
+	for _, c := range changes {
+		if len(c.AddEnvs) > 0 {
+			planned = append(planned, c)
+		}
+	}
+	return planned
+}
+
+// printImportChanges prints the diff against the current config
+func printImportChanges(changes []importChange, skipped []string) {
+	if len(changes) == 0 {
+		fmt.Printf("\n%sNothing to import: the config already has every project environment%s\n", colorDim, colorReset)
+	}
+	for _, c := range changes {
+		if c.Existing < 0 {
+			fmt.Printf("%s  + %s%s %s(%s)%s: %s\n", colorGreen, c.Project, colorReset, colorDim, c.ID, colorReset,
+				strings.Join(c.AddEnvs, ", "))
+		} else {
+			fmt.Printf("%s  ~ %s%s %s(%s)%s: %s+ %s%s\n", colorYellow, c.Project, colorReset, colorDim, c.ID, colorReset,
+				colorGreen, strings.Join(c.AddEnvs, ", "), colorReset)
+		}
+	}
+	if len(skipped) > 0 {
+		fmt.Printf("\n%s⚠️  Skipped %d project(s) not matching %q or --envs: %s%s\n",
+			colorYellow, len(skipped), importPattern, strings.Join(skipped, ", "), colorReset)
+	}
+	fmt.Println()
+}
+
+// applyImport merges the planned changes into the config
+func applyImport(cfg *Config, changes []importChange) {
+	// Existing projects first: appending new ones may move the slice
+	for _, c := range changes {
+		if c.Existing >= 0 {
+			project := &cfg.Projects[c.Existing]
+			project.Environments = append(project.Environments, c.AddEnvs...)
+		}
+	}
+	for _, c := range changes {
+		if c.Existing < 0 {
+			cfg.Projects = append(cfg.Projects, Project{Name: c.Project, ID: c.ID, Environments: c.AddEnvs})
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package gcp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		csv      string
+		wantIDs  []string
+		wantEnvs []importedEnv
+	}{
+		{
+			name:    "IDs only",
+			csv:     "projectId,name\nairasia-move-prod,AirAsia\n\nairasia-move-dev,AirAsia\n",
+			wantIDs: []string{"airasia-move-prod", "airasia-move-dev"},
+		},
+		{
+			name: "environment column trims the suffix",
+			csv: "name,project_id,environment\n" +
+				"AirAsia,airasia-move-prod,prod\n" +
+				"AirAsia,AIRASIA-MOVE-STG,stg\n" +
+				",sandbox-dev,dev\n" +
+				"Shared,shared,prod\n",
+			wantEnvs: []importedEnv{
+				{Name: "AirAsia", ID: "airasia-move", Env: "prod"},
+				{Name: "AirAsia", ID: "AIRASIA-MOVE", Env: "stg"},
+				{Name: "sandbox", ID: "sandbox", Env: "dev"},
+				{Name: "Shared", ID: "shared", Env: "prod"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, envs, err := parseImportCSV([]byte(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %q, want %q", ids, tt.wantIDs)
+			}
+			if !reflect.DeepEqual(envs, tt.wantEnvs) {
+				t.Errorf("envs = %+v, want %+v", envs, tt.wantEnvs)
+			}
+		})
+	}
+}
+
+func TestParseImportCSVNeedsIDColumn(t *testing.T) {
+	if _, _, err := parseImportCSV([]byte("name,env\nAirAsia,prod\n")); err == nil {
+		t.Error("err = nil, want an error for a CSV without a project ID column")
+	}
+}
+
+func TestSplitProjectID(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		id      string
+		want    importedEnv
+		wantOK  bool
+	}{
+		{
+			name:    "lowercase pattern",
+			pattern: "acme-{name}-{env}",
+			id:      "acme-billing-prod",
+			want:    importedEnv{Name: "billing", ID: "acme-billing", Env: "prod"},
+			wantOK:  true,
+		},
+		{
+			name:    "pattern with capitals",
+			pattern: "Acme-{name}-{env}",
+			id:      "acme-billing-staging",
+			want:    importedEnv{Name: "billing", ID: "acme-billing", Env: "staging"},
+			wantOK:  true,
+		},
+		{
+			name:    "other prefix",
+			pattern: "Acme-{name}-{env}",
+			id:      "other-billing-prod",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := patternRegexp(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := splitProjectID(re, tt.id)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("splitProjectID(%q) = %+v, %v; want %+v, %v", tt.id, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// cmd/gcp/info.go
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Info flags
+	infoJSON bool
+)
+
+// projectInfo is the JSON form of a project (and optional environment) card
+type projectInfo struct {
+	Project     string `json:"project"`
+	ProjectID   string `json:"projectId"`
+	Environment string `json:"environment,omitempty"`
+	Metadata
+}
+
+// infoCmd represents the gcp info command
+var infoCmd = &cobra.Command{
+	Use:   "info <project> [env]",
+	Short: "Show owners, on-call, channels and runbooks of a project",
+	Long: `Shows the metadata attached to a project in the config — owners, on-call
+rotation, Slack channel, repository, runbooks, labels and notes — as a card.
+
+With an environment, its "envMetadata" entry is merged over the project's:
+single values are overridden, labels are merged and runbooks are appended.`,
+	Example: `  sun gcp info air
+  sun gcp info air prod
+  sun gcp info air prod --json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runInfoCommand,
+}
+
+func init() {
+	infoCmd.Flags().BoolVar(&infoJSON, "json", false, "Print as JSON")
+
+	GcpCmd.AddCommand(infoCmd)
+}
+
+// runInfoCommand prints the metadata card of a project or project environment
+func runInfoCommand(cmd *cobra.Command, args []string) error {
+	project := findMatchingProject(args[0])
+	if project == nil {
+		return fmt.Errorf("no project matching '%s'", args[0])
+	}
+
+	info := projectInfo{
+		Project:   project.Name,
+		ProjectID: project.ID,
+		Metadata:  project.Metadata,
+	}
+
+	if len(args) > 1 {
+		_, env, err := resolveProjectEnv(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		info.Environment = env
+		info.ProjectID = projectID(project, env)
+		info.Metadata = environmentMetadata(project, env)
+	}
+
+	if infoJSON {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+
+	printInfoCard(info)
+	return nil
+}
+
+// environmentMetadata merges the environment's metadata over the project's
+func environmentMetadata(project *Project, env string) Metadata {
+	merged := project.Metadata
+	override, ok := project.EnvMetadata[env]
+	if !ok {
+		return merged
+	}
+
+	if len(override.Owners) > 0 {
+		merged.Owners = override.Owners
+	}
+	if override.OnCall != "" {
+		merged.OnCall = override.OnCall
+	}
+	if override.Slack != "" {
+		merged.Slack = override.Slack
+	}
+	if override.Repo != "" {
+		merged.Repo = override.Repo
+	}
+	if override.Notes != "" {
+		merged.Notes = strings.TrimSpace(merged.Notes + "\n" + override.Notes)
+	}
+
+	merged.Runbooks = append(append([]Link{}, project.Runbooks...), override.Runbooks...)
+
+	if len(override.Labels) > 0 {
+		merged.Labels = make(map[string]string, len(project.Labels)+len(override.Labels))
+		for k, v := range project.Labels {
+			merged.Labels[k] = v
+		}
+		for k, v := range override.Labels {
+			merged.Labels[k] = v
+		}
+	}
+
+	return merged
+}
+
+// matchingEnvironments returns the environments whose merged labels match
+// every selector ("key=value" or "key")
+func matchingEnvironments(project *Project, selectors []string) []string {
+	var envs []string
+	for _, env := range project.Environments {
+		if labelsMatch(environmentMetadata(project, env).Labels, selectors) {
+			envs = append(envs, env)
+		}
+	}
+	return envs
+}
+
+// labelsMatch reports whether labels satisfy every selector
+func labelsMatch(labels map[string]string, selectors []string) bool {
+	for _, selector := range selectors {
+		key, want, hasValue := strings.Cut(selector, "=")
+		value, ok := labels[strings.TrimSpace(key)]
+		if !ok {
+			return false
+		}
+		if hasValue && !strings.EqualFold(value, strings.TrimSpace(want)) {
+			return false
+		}
+	}
+	return true
+}
+
+// formatLabels formats labels as sorted key=value pairs
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// printInfoCard prints a project metadata card
+func printInfoCard(info projectInfo) {
+	title := info.Project
+	if info.Environment != "" {
+		title += " / " + info.Environment
+	}
+
+	fmt.Printf("\n%s%sℹ️  %s%s\n", colorBold, colorBlue, title, colorReset)
+	fmt.Printf("%s  Project ID:  %s%s\n", colorDim, colorReset, info.ProjectID)
+
+	field := func(label, value string) {
+		if value != "" {
+			fmt.Printf("%s  %-12s %s%s\n", colorDim, label+":", colorReset, value)
+		}
+	}
+	field("Owners", strings.Join(info.Owners, ", "))
+	field("On-call", info.OnCall)
+	field("Slack", info.Slack)
+	field("Repo", info.Repo)
+	if len(info.Labels) > 0 {
+		field("Labels", formatLabels(info.Labels))
+	}
+
+	if len(info.Runbooks) > 0 {
+		fmt.Printf("\n%sRunbooks:%s\n", colorBold, colorReset)
+		for _, r := range info.Runbooks {
+			fmt.Printf("  • %s → %s%s%s\n", r.Title, colorBlue, r.URL, colorReset)
+		}
+	}
+
+	if info.Notes != "" {
+		fmt.Printf("\n%sNotes:%s\n", colorBold, colorReset)
+		for _, line := range strings.Split(info.Notes, "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+
+	if len(info.Owners) == 0 && info.OnCall == "" && info.Slack == "" && info.Repo == "" &&
+		len(info.Runbooks) == 0 && len(info.Labels) == 0 && info.Notes == "" {
+		fmt.Printf("\n%sNo metadata yet. Add owners, onCall, slack, repo, runbooks, labels or notes to the project in %s%s\n",
+			colorDim, configFile, colorReset)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,105 @@
+// cmd/gcp/open.go
+package gcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Target names a console page for callers outside this package, such as
+// sun ai open. Names must match the config exactly, ignoring case; unlike
+// command-line arguments, partial names are not accepted, so a guessed name
+// cannot open a different page. A project may also be given by its ID.
+type Target struct {
+	Project string `json:"project"`
+	Env     string `json:"env"`
+	Service string `json:"service"`
+	// LogFilter is a Logs Explorer query, e.g. severity>=ERROR; only used
+	// with a Logs Explorer service
+	LogFilter string `json:"logFilter,omitempty"`
+}
+
+// Catalog returns the configured projects and services
+func Catalog() ([]Project, []Service) {
+	return config.Projects, config.Services
+}
+
+// serviceNames returns the names of the services offered in a project environment
+func serviceNames(project *Project, env string) []string {
+	var names []string
+	for _, s := range availableServices(project, env) {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+// ResolveTarget matches a target against the config. The result holds the
+// configured names, so it can be shown before the page is opened.
+func ResolveTarget(t Target) (Target, error) {
+	sel, err := resolveTarget(t)
+	if err != nil {
+		return Target{}, err
+	}
+	t.Project, t.Env, t.Service = sel.Project.Name, sel.Env, sel.Service.Name
+	if !isLogsExplorer(sel.Service) {
+		t.LogFilter = ""
+	}
+	return t, nil
+}
+
+// OpenTarget opens the console page of a target like the launcher does,
+// caching it for --repeat and recording it in the usage log
+func OpenTarget(t Target) error {
+	sel, err := resolveTarget(t)
+	if err != nil {
+		return err
+	}
+	if isLogsExplorer(sel.Service) {
+		logQuery = t.LogFilter
+	}
+	return openSelection(sel, viaAI)
+}
+
+// resolveTarget matches a target without printing anything
+func resolveTarget(t Target) (*selection, error) {
+	var project *Project
+	for i := range config.Projects {
+		p := &config.Projects[i]
+		if strings.EqualFold(p.Name, t.Project) || strings.EqualFold(p.ID, t.Project) {
+			project = p
+			break
+		}
+	}
+	if project == nil {
+		names := make([]string, len(config.Projects))
+		for i, p := range config.Projects {
+			names[i] = p.Name
+		}
+		return nil, fmt.Errorf("no project named '%s'. Available: %s", t.Project, strings.Join(names, ", "))
+	}
+
+	env := ""
+	for _, e := range project.Environments {
+		if strings.EqualFold(e, t.Env) {
+			env = e
+			break
+		}
+	}
+	if env == "" {
+		return nil, fmt.Errorf("no environment '%s' in %s. Available: %s",
+			t.Env, project.Name, strings.Join(project.Environments, ", "))
+	}
+
+	for _, s := range availableServices(project, env) {
+		if strings.EqualFold(s.Name, t.Service) {
+			return &selection{Project: project, Env: env, Service: s}, nil
+		}
+	}
+	return nil, fmt.Errorf("no service named '%s' in %s/%s. Available: %s",
+		t.Service, project.Name, env, strings.Join(serviceNames(project, env), ", "))
+}
+
+// isLogsExplorer reports whether a service opens Logs Explorer, which takes a {query}
+func isLogsExplorer(service *Service) bool {
+	return strings.HasPrefix(service.Path, "logs/query")
+}
@@ -0,0 +1,77 @@
+package gcp
+
+import (
+	"testing"
+)
+
+// useConfig replaces the loaded config for the duration of a test
+func useConfig(t *testing.T, cfg Config) {
+	t.Helper()
+	saved := config
+	config = cfg
+	t.Cleanup(func() { config = saved })
+}
+
+func TestResolveTarget(t *testing.T) {
+	useConfig(t, Config{
+		Projects: []Project{
+			{Name: "AirAsia MOVE", ID: "airasia-move", Environments: []string{"prod", "staging"}},
+			{Name: "AirAsia Ride", ID: "airasia-ride", Environments: []string{"prod"}},
+		},
+		Services: []Service{
+			{Name: "Logs Explorer", Path: "logs/query"},
+			{Name: "Cloud Run", Path: "run"},
+			{Name: "Cloud Run Jobs", Path: "run/jobs"},
+		},
+	})
+
+	tests := []struct {
+		name    string
+		target  Target
+		want    Target
+		wantErr bool
+	}{
+		{
+			name:   "exact names",
+			target: Target{Project: "AirAsia MOVE", Env: "prod", Service: "Cloud Run"},
+			want:   Target{Project: "AirAsia MOVE", Env: "prod", Service: "Cloud Run"},
+		},
+		{
+			name:   "case and project ID",
+			target: Target{Project: "AIRASIA-RIDE", Env: "Prod", Service: "cloud run jobs"},
+			want:   Target{Project: "AirAsia Ride", Env: "prod", Service: "Cloud Run Jobs"},
+		},
+		{
+			name:   "log filter kept for Logs Explorer",
+			target: Target{Project: "airasia move", Env: "staging", Service: "Logs Explorer", LogFilter: "severity>=ERROR"},
+			want:   Target{Project: "AirAsia MOVE", Env: "staging", Service: "Logs Explorer", LogFilter: "severity>=ERROR"},
+		},
+		{
+			name:   "log filter dropped for other services",
+			target: Target{Project: "AirAsia MOVE", Env: "prod", Service: "Cloud Run", LogFilter: "severity>=ERROR"},
+			want:   Target{Project: "AirAsia MOVE", Env: "prod", Service: "Cloud Run"},
+		},
+		{name: "partial project", target: Target{Project: "AirAsia", Env: "prod", Service: "Cloud Run"}, wantErr: true},
+		{name: "partial service", target: Target{Project: "AirAsia MOVE", Env: "prod", Service: "Logs"}, wantErr: true},
+		{name: "partial environment", target: Target{Project: "AirAsia MOVE", Env: "stag", Service: "Cloud Run"}, wantErr: true},
+		{name: "unknown service", target: Target{Project: "AirAsia MOVE", Env: "prod", Service: "BigQuery"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveTarget(tt.target)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ResolveTarget = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveTarget = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// cmd/gcp/params.go
+package gcp
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Time range and location flags
+	sinceFlag  string
+	fromFlag   string
+	toFlag     string
+	regionFlag string
+	zoneFlag   string
+
+	// logQuery is the Logs Explorer query for {query}, set by sun ai open
+	logQuery string
+
+	// timeRange is the range parsed from the flags; zero when none was given
+	timeRange struct {
+		Start, End time.Time
+		Relative   time.Duration // set by --since: the range ends now
+	}
+)
+
+// placeholderPattern matches a {placeholder} in a URL or command template
+var placeholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// addURLParamFlags registers the time range and location flags on a command
+func addURLParamFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&sinceFlag, "since", "", "Time range ending now, e.g. 30m, 6h, 2d")
+	cmd.Flags().StringVar(&fromFlag, "from", "", "Start of the time range (date, RFC 3339 time or duration ago)")
+	cmd.Flags().StringVar(&toFlag, "to", "", "End of the time range (default now)")
+	cmd.Flags().StringVar(&regionFlag, "region", "", "Region, overriding the project's default region")
+	cmd.Flags().StringVar(&zoneFlag, "zone", "", "Zone; also sets the region when --region is not given")
+}
+
+// parseURLParamFlags validates the time range flags and stores the range
+func parseURLParamFlags() error {
+	now := time.Now()
+
+	switch {
+	case sinceFlag != "" && (fromFlag != "" || toFlag != ""):
+		return fmt.Errorf("use either --since or --from/--to")
+	case sinceFlag != "":
+		start, err := parseSince(sinceFlag, now)
+		if err != nil {
+			return err
+		}
+		timeRange.Start, timeRange.End = start, now
+		timeRange.Relative = now.Sub(start)
+	case fromFlag != "":
+		start, err := parseSince(fromFlag, now)
+		if err != nil {
+			return err
+		}
+		end := now
+		if toFlag != "" {
+			if end, err = parseSince(toFlag, now); err != nil {
+				return err
+			}
+		}
+		if !start.Before(end) {
+			return fmt.Errorf("--from must be before --to")
+		}
+		timeRange.Start, timeRange.End = start, end
+	case toFlag != "":
+		return fmt.Errorf("--to needs --from")
+	}
+
+	return nil
+}
+
+// urlVariables returns the time range and location placeholders for a
+// project. Unset ones are empty, which drops the URL parts that use them.
+//
+//	{start}, {end}  RFC 3339 times in UTC
+//	{duration}      ISO 8601 duration, with --since only (PT30M)
+//	{range}         {duration} with --since, "{start}/{end}" with --from/--to
+//	{region}        --region, the region of --zone, or the project's region
+//	{zone}          --zone
+//	{query}         the Logs Explorer query of sun ai open
+func urlVariables(project *Project) map[string]string {
+	vars := map[string]string{
+		"start":    "",
+		"end":      "",
+		"duration": "",
+		"range":    "",
+		"region":   project.Region,
+		"zone":     zoneFlag,
+		"query":    logQuery,
+	}
+
+	if !timeRange.Start.IsZero() {
+		vars["start"] = timeRange.Start.UTC().Format(time.RFC3339)
+		vars["end"] = timeRange.End.UTC().Format(time.RFC3339)
+		vars["range"] = vars["start"] + "/" + vars["end"]
+		if timeRange.Relative > 0 {
+			vars["duration"] = isoDuration(timeRange.Relative)
+			vars["range"] = vars["duration"]
+		}
+	}
+
+	switch {
+	case regionFlag != "":
+		vars["region"] = regionFlag
+	case zoneFlag != "":
+		// asia-southeast1-b → asia-southeast1
+		if i := strings.LastIndex(zoneFlag, "-"); i > 0 {
+			vars["region"] = zoneFlag[:i]
+		}
+	}
+
+	return vars
+}
+
+// fillTemplate replaces the placeholders of vars in tmpl, escaping the values.
+// It reports false when a placeholder of vars has no value; placeholders
+// not in vars are left as they are.
+func fillTemplate(tmpl string, vars map[string]string, escape func(string) string) (string, bool) {
+	ok := true
+	filled := placeholderPattern.ReplaceAllStringFunc(tmpl, func(m string) string {
+		value, known := vars[m[1:len(m)-1]]
+		if !known {
+			return m
+		}
+		if value == "" {
+			ok = false
+			return m
+		}
+		return escape(value)
+	})
+	return filled, ok
+}
+
+// resolveURLPath fills the variables in a service path. Matrix parameters
+// (";name=value" segments, as used by Logs Explorer) are dropped when a
+// variable they use is unset.
+func resolveURLPath(path string, vars map[string]string) string {
+	parts := strings.Split(path, ";")
+	resolved, _ := fillTemplate(parts[0], vars, url.PathEscape)
+	kept := []string{resolved}
+	for _, part := range parts[1:] {
+		if filled, ok := fillTemplate(part, vars, url.PathEscape); ok {
+			kept = append(kept, filled)
+		}
+	}
+	return strings.Join(kept, ";")
+}
+
+// resolveURLQuery returns the service's query parameters, sorted by name,
+// leaving out those whose template uses an unset variable
+func resolveURLQuery(params map[string]string, vars map[string]string) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var query strings.Builder
+	for _, name := range names {
+		if value, ok := fillTemplate(params[name], vars, url.QueryEscape); ok {
+			fmt.Fprintf(&query, "&%s=%s", url.QueryEscape(name), value)
+		}
+	}
+	return query.String()
+}
+
+// isoDuration formats a duration as ISO 8601, e.g. PT1H30M or P2D
+func isoDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("P%dD", d/(24*time.Hour))
+	}
+
+	s := "PT"
+	if h := d / time.Hour; h > 0 {
+		s += fmt.Sprintf("%dH", h)
+	}
+	if m := d % time.Hour / time.Minute; m > 0 {
+		s += fmt.Sprintf("%dM", m)
+	}
+	if sec := d % time.Minute / time.Second; sec > 0 || s == "PT" {
+		s += fmt.Sprintf("%dS", sec)
+	}
+	return s
+}
+
+// usesVariable reports whether the path or parameters of a service use any
+// of the named placeholders
+func usesVariable(service *Service, names ...string) bool {
+	templates := []string{service.Path}
+	for _, param := range service.Params {
+		templates = append(templates, param)
+	}
+	for _, tmpl := range templates {
+		for _, m := range placeholderPattern.FindAllStringSubmatch(tmpl, -1) {
+			for _, name := range names {
+				if m[1] == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// describeTimeRange describes the time range for the summary, or returns ""
+func describeTimeRange() string {
+	switch {
+	case timeRange.Start.IsZero():
+		return ""
+	case timeRange.Relative > 0:
+		// 30m0s → 30m, 2h0m0s → 2h
+		d := timeRange.Relative.Round(time.Second).String()
+		if strings.HasSuffix(d, "m0s") {
+			d = strings.TrimSuffix(d, "0s")
+		}
+		if strings.HasSuffix(d, "h0m") {
+			d = strings.TrimSuffix(d, "0m")
+		}
+		return "last " + d
+	default:
+		const layout = "2006-01-02 15:04"
+		return timeRange.Start.Format(layout) + " → " + timeRange.End.Format(layout)
+	}
+}
@@ -0,0 +1,278 @@
+// cmd/gcp/services.go
+package gcp
+
+import (
+	"sort"
+	"strings"
+)
+
+// allServices returns every configured service
+func allServices() []*Service {
+	services := make([]*Service, len(config.Services))
+	for i := range config.Services {
+		services[i] = &config.Services[i]
+	}
+	return services
+}
+
+// availableServices returns the services offered in a project environment
+func availableServices(project *Project, env string) []*Service {
+	var services []*Service
+	for i := range config.Services {
+		if config.Services[i].availableIn(project, env) {
+			services = append(services, &config.Services[i])
+		}
+	}
+	return services
+}
+
+// availableIn reports whether the service is offered in a project environment
+func (s *Service) availableIn(project *Project, env string) bool {
+	if s.Include != nil && !s.Include.matches(project, env) {
+		return false
+	}
+	if s.Exclude != nil && s.Exclude.matches(project, env) {
+		return false
+	}
+	return true
+}
+
+// matches reports whether the scope selects a project environment
+func (sc *ServiceScope) matches(project *Project, env string) bool {
+	if len(sc.Projects) > 0 && !containsFold(sc.Projects, project.Name) && !containsFold(sc.Projects, project.ID) {
+		return false
+	}
+	if len(sc.Environments) > 0 && !containsFold(sc.Environments, env) {
+		return false
+	}
+	return true
+}
+
+// findDefaultService returns the default service of a project environment,
+// if one is configured and available there
+func findDefaultService(project *Project, env string) *Service {
+	name, ok := project.DefaultServices[env]
+	if !ok {
+		return nil
+	}
+	return findMatchingService(availableServices(project, env), name)
+}
+
+// containsFold reports whether items contains s, ignoring case
+func containsFold(items []string, s string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// otherCategory is the group of services without a category
+const otherCategory = "Other"
+
+// serviceOption is a row of the service picker
+type serviceOption struct {
+	Name     string
+	Category string
+	Header   bool // category header, not selectable
+	Back     bool // "← Go Back"
+	Service  *Service
+}
+
+// serviceOptions are the rows of the service picker
+type serviceOptions []serviceOption
+
+// buildServiceOptions lays out the picker: the default service (if any),
+// "← Go Back", then the services sorted by name under category headers.
+// Headers are only shown when at least one service has a category.
+func buildServiceOptions(services []*Service, defaultService *Service) serviceOptions {
+	var options serviceOptions
+	if defaultService != nil {
+		options = append(options, serviceOption{Name: defaultService.Name, Category: serviceCategory(defaultService), Service: defaultService})
+	}
+	options = append(options, serviceOption{Name: "← Go Back", Back: true})
+
+	var rest []*Service
+	for _, s := range services {
+		if s != defaultService {
+			rest = append(rest, s)
+		}
+	}
+	sort.Slice(rest, func(a, b int) bool { return rest[a].Name < rest[b].Name })
+
+	groups := groupByCategory(rest)
+	showHeaders := len(groups) > 1 || (len(groups) == 1 && groups[0].Category != otherCategory)
+	for _, g := range groups {
+		if showHeaders {
+			options = append(options, serviceOption{Name: "── " + g.Category + " ──", Category: g.Category, Header: true})
+		}
+		for _, s := range g.Services {
+			options = append(options, serviceOption{Name: s.Name, Category: g.Category, Service: s})
+		}
+	}
+	return options
+}
+
+// cursor returns the initial picker position: the previous answer when
+// revisiting, otherwise the first service row
+func (o serviceOptions) cursor(current string) int {
+	first := -1
+	for i, option := range o {
+		if option.Service == nil {
+			continue
+		}
+		if option.Name == current {
+			return i
+		}
+		if first < 0 {
+			first = i
+		}
+	}
+	return max(first, 0)
+}
+
+// matches is the picker searcher. Input may start with a category prefix
+// ("obs:" or "obs:logs"); headers stay visible while a service under them matches.
+func (o serviceOptions) matches(index int, input string) bool {
+	option := o[index]
+
+	// Don't filter the back option
+	if option.Back {
+		return strings.Contains("back", strings.ToLower(strings.TrimSpace(input)))
+	}
+
+	category, rest, hasCategory := splitCategoryFilter(input)
+	if !hasCategory {
+		rest = input
+	}
+
+	if option.Header {
+		for _, other := range o {
+			if other.Service != nil && other.Category == option.Category &&
+				(!hasCategory || categoryMatches(other.Category, category)) && serviceNameMatches(other.Name, rest) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if hasCategory && !categoryMatches(option.Category, category) {
+		return false
+	}
+	return serviceNameMatches(option.Name, rest)
+}
+
+// serviceAbbreviations maps common abbreviations to words of service names
+var serviceAbbreviations = map[string][]string{
+	"k8s":     {"kubernetes"},
+	"gke":     {"kubernetes"},
+	"sql":     {"sql", "database"},
+	"db":      {"sql", "database"},
+	"storage": {"storage", "gcs"},
+	"gcs":     {"storage"},
+	"logs":    {"logs", "explorer"},
+	"iam":     {"iam", "admin"},
+	"compute": {"compute", "engine", "vm"},
+	"vm":      {"compute", "engine"},
+	"bq":      {"bigquery"},
+	"pubsub":  {"pub", "sub"},
+	"run":     {"run"},
+	"fn":      {"functions"},
+	"lambda":  {"functions"},
+}
+
+// serviceNameMatches fuzzy-matches search input against a service name
+func serviceNameMatches(service, input string) bool {
+	name := strings.ReplaceAll(strings.ToLower(service), " ", "")
+	input = strings.ReplaceAll(strings.ToLower(input), " ", "")
+
+	// Fuzzy search: check if all characters in input appear in order
+	if input == "" {
+		return true
+	}
+
+	// Check abbreviation match
+	if matches, ok := serviceAbbreviations[input]; ok {
+		for _, match := range matches {
+			if strings.Contains(name, match) {
+				return true
+			}
+		}
+	}
+
+	// Fuzzy character matching
+	inputIdx := 0
+	for _, char := range name {
+		if inputIdx < len(input) && char == rune(input[inputIdx]) {
+			inputIdx++
+		}
+	}
+	return inputIdx == len(input)
+}
+
+// serviceCategory returns the category of a service, or otherCategory
+func serviceCategory(s *Service) string {
+	if strings.TrimSpace(s.Category) == "" {
+		return otherCategory
+	}
+	return s.Category
+}
+
+// splitCategoryFilter splits "obs:logs" into the category prefix and the rest
+func splitCategoryFilter(filter string) (category, rest string, ok bool) {
+	category, rest, ok = strings.Cut(filter, ":")
+	if !ok {
+		return "", filter, false
+	}
+	return strings.TrimSpace(category), strings.TrimSpace(rest), true
+}
+
+// categoryMatches reports whether a category starts with the given prefix
+func categoryMatches(category, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(category), strings.ToLower(prefix))
+}
+
+// servicesInCategory returns the services whose category starts with prefix
+func servicesInCategory(services []*Service, prefix string) []*Service {
+	var matched []*Service
+	for _, s := range services {
+		if categoryMatches(serviceCategory(s), prefix) {
+			matched = append(matched, s)
+		}
+	}
+	return matched
+}
+
+// serviceGroup is a category and its services
+type serviceGroup struct {
+	Category string
+	Services []*Service
+}
+
+// groupByCategory groups services by category, with categories sorted by
+// name and uncategorized services last; services keep their order
+func groupByCategory(services []*Service) []serviceGroup {
+	byCategory := map[string][]*Service{}
+	for _, s := range services {
+		category := serviceCategory(s)
+		byCategory[category] = append(byCategory[category], s)
+	}
+
+	categories := make([]string, 0, len(byCategory))
+	for category := range byCategory {
+		if category != otherCategory {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	if _, ok := byCategory[otherCategory]; ok {
+		categories = append(categories, otherCategory)
+	}
+
+	groups := make([]serviceGroup, len(categories))
+	for i, category := range categories {
+		groups[i] = serviceGroup{Category: category, Services: byCategory[category]}
+	}
+	return groups
+}
@@ -0,0 +1,342 @@
+// cmd/gcp/stats.go
+package gcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itsiqbal/sun-cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// Ways a page was opened, recorded in the usage log
+const (
+	viaFlags       = "flags"       // every answer came from arguments or flags
+	viaInteractive = "interactive" // at least one prompt was shown
+	viaRepeat      = "repeat"      // --repeat
+	viaFind        = "find"        // the omnibox
+	viaAI          = "ai"          // sun ai open
+)
+
+// usageEntry is one line of the usage log
+type usageEntry struct {
+	Time     time.Time `json:"time"`
+	Project  string    `json:"project,omitempty"`
+	Env      string    `json:"env,omitempty"`
+	Service  string    `json:"service,omitempty"`
+	Bookmark string    `json:"bookmark,omitempty"`
+	Via      string    `json:"via"`
+}
+
+// usageCount is a name with the number of times it was opened
+type usageCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// usageStats is the summary printed by `sun gcp stats`
+type usageStats struct {
+	Since     *time.Time     `json:"since,omitempty"`
+	Total     int            `json:"total"`
+	Projects  []usageCount   `json:"projects"`
+	Services  []usageCount   `json:"services"`
+	Bookmarks []usageCount   `json:"bookmarks"`
+	Via       map[string]int `json:"via"`
+	Days      []usageCount   `json:"days"`
+	Unused    unusedEntries  `json:"unused"`
+}
+
+// unusedEntries lists config entries that were never opened in the window
+type unusedEntries struct {
+	Projects  []string `json:"projects"`
+	Services  []string `json:"services"`
+	Bookmarks []string `json:"bookmarks"`
+}
+
+var (
+	// Stats flags
+	statsSince string
+	statsTop   int
+	statsJSON  bool
+)
+
+// statsCmd represents the gcp stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show which projects, services and bookmarks are opened most",
+	Long: `Every page opened by the launcher is recorded locally, with the time and how
+it was reached (flags, interactive, repeat or find), in an append-only log:
+
+  ~/.config/sun-cli/gcp-usage.jsonl
+
+This command summarises the log: top projects, services and bookmarks, opens
+per day, and the config entries nobody opened, to help curate the shared config.
+
+--since takes a duration such as 12h, 7d or 4w, or a date (YYYY-MM-DD).`,
+	Example: `  sun gcp stats
+  sun gcp stats --since 30d
+  sun gcp stats --since 2026-01-01 --json`,
+	Args: cobra.NoArgs,
+	RunE: runStatsCommand,
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsSince, "since", "", "Only count opens after this duration ago or date")
+	statsCmd.Flags().IntVar(&statsTop, "top", 10, "Number of top projects, services and bookmarks to show")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Print as JSON")
+
+	GcpCmd.AddCommand(statsCmd)
+}
+
+// runStatsCommand summarises the usage log
+func runStatsCommand(cmd *cobra.Command, args []string) error {
+	if statsTop < 1 {
+		return fmt.Errorf("--top must be at least 1")
+	}
+
+	var since time.Time
+	if statsSince != "" {
+		var err error
+		since, err = parseSince(statsSince, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	entries, err := loadUsage(since)
+	if err != nil {
+		return err
+	}
+
+	stats := summariseUsage(entries)
+	if !since.IsZero() {
+		stats.Since = &since
+	}
+
+	if statsJSON {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	printStats(stats)
+	return nil
+}
+
+// recordUsage appends an open to the usage log. Failures only warn: the log
+// must never get in the way of opening a page.
+func recordUsage(entry usageEntry) {
+	entry.Time = time.Now().UTC()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := store.Append(usageFile, append(data, '\n'), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "%sWarning: Unable to record usage: %v%s\n", colorYellow, err, colorReset)
+	}
+}
+
+// loadUsage reads the usage log, keeping entries at or after since.
+// Lines that cannot be parsed (e.g. cut short by a crash) are skipped.
+func loadUsage(since time.Time) ([]usageEntry, error) {
+	f, err := os.Open(usageFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []usageEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry usageEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.Time.Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage log: %w", err)
+	}
+	return entries, nil
+}
+
+// parseSince parses a duration back from now ("12h", "7d", "4w"), a date or a time
+func parseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	// Days and weeks are not understood by time.ParseDuration
+	if n, ok := strings.CutSuffix(s, "d"); ok {
+		if days, err := strconv.Atoi(n); err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if n, ok := strings.CutSuffix(s, "w"); ok {
+		if weeks, err := strconv.Atoi(n); err == nil && weeks >= 0 {
+			return now.AddDate(0, 0, -7*weeks), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time '%s'. Use a duration such as 12h, 7d or 4w, or a date (YYYY-MM-DD)", s)
+}
+
+// summariseUsage counts the entries by project, service, bookmark, source and day
+func summariseUsage(entries []usageEntry) usageStats {
+	projects := map[string]int{}
+	services := map[string]int{}
+	bookmarks := map[string]int{}
+	days := map[string]int{}
+	stats := usageStats{Total: len(entries), Via: map[string]int{}}
+
+	for _, e := range entries {
+		if e.Bookmark != "" {
+			bookmarks[e.Bookmark]++
+		} else {
+			projects[e.Project]++
+			services[e.Service]++
+		}
+		stats.Via[e.Via]++
+		days[e.Time.Local().Format("2006-01-02")]++
+	}
+
+	stats.Projects = sortedCounts(projects)
+	stats.Services = sortedCounts(services)
+	stats.Bookmarks = sortedCounts(bookmarks)
+
+	// Days in calendar order
+	stats.Days = []usageCount{}
+	for day, count := range days {
+		stats.Days = append(stats.Days, usageCount{Name: day, Count: count})
+	}
+	sort.Slice(stats.Days, func(i, j int) bool {
+		return stats.Days[i].Name < stats.Days[j].Name
+	})
+
+	stats.Unused = unusedEntries{Projects: []string{}, Services: []string{}, Bookmarks: []string{}}
+	for _, p := range config.Projects {
+		if projects[p.Name] == 0 {
+			stats.Unused.Projects = append(stats.Unused.Projects, p.Name)
+		}
+	}
+	for _, s := range config.Services {
+		if services[s.Name] == 0 {
+			stats.Unused.Services = append(stats.Unused.Services, s.Name)
+		}
+	}
+	for _, b := range config.Bookmarks {
+		if bookmarks[b.Name] == 0 {
+			stats.Unused.Bookmarks = append(stats.Unused.Bookmarks, b.Name)
+		}
+	}
+
+	return stats
+}
+
+// sortedCounts orders counts by count, then name
+func sortedCounts(counts map[string]int) []usageCount {
+	sorted := make([]usageCount, 0, len(counts))
+	for name, count := range counts {
+		sorted = append(sorted, usageCount{Name: name, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// printStats prints the usage summary
+func printStats(stats usageStats) {
+	title := "📊 Usage"
+	if stats.Since != nil {
+		title += " since " + stats.Since.Format("2006-01-02 15:04")
+	}
+	fmt.Printf("\n%s%s%s%s %s(%d opens)%s\n", colorBold, colorBlue, title, colorReset, colorDim, stats.Total, colorReset)
+
+	if stats.Total == 0 {
+		if stats.Since != nil {
+			fmt.Printf("\n%sNothing opened in this window%s\n\n", colorDim, colorReset)
+		} else {
+			fmt.Printf("\n%sNothing recorded yet. Pages opened with sun gcp are logged to %s%s\n\n", colorDim, usageFile, colorReset)
+		}
+		return
+	}
+
+	printTopCounts("Top projects", stats.Projects)
+	printTopCounts("Top services", stats.Services)
+	printTopCounts("Top bookmarks", stats.Bookmarks)
+
+	var sources []string
+	for _, via := range []string{viaFlags, viaInteractive, viaRepeat, viaFind, viaAI} {
+		if n := stats.Via[via]; n > 0 {
+			sources = append(sources, fmt.Sprintf("%s %d", via, n))
+		}
+	}
+	fmt.Printf("\n%sOpened via:%s %s\n", colorBold, colorReset, strings.Join(sources, ", "))
+
+	// Bars are scaled to the busiest day
+	fmt.Printf("\n%sPer day:%s\n", colorBold, colorReset)
+	busiest := 0
+	for _, d := range stats.Days {
+		busiest = max(busiest, d.Count)
+	}
+	for _, d := range stats.Days {
+		bar := strings.Repeat("█", max(1, d.Count*30/busiest))
+		fmt.Printf("  %s  %s%s%s %d\n", d.Name, colorGreen, bar, colorReset, d.Count)
+	}
+
+	if len(stats.Unused.Projects)+len(stats.Unused.Services)+len(stats.Unused.Bookmarks) > 0 {
+		fmt.Printf("\n%sNever opened:%s\n", colorBold, colorReset)
+		printUnused("Projects", stats.Unused.Projects)
+		printUnused("Services", stats.Unused.Services)
+		printUnused("Bookmarks", stats.Unused.Bookmarks)
+	}
+	fmt.Println()
+}
+
+// printTopCounts prints the first --top counts under a heading
+func printTopCounts(heading string, counts []usageCount) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Printf("\n%s%s:%s\n", colorBold, heading, colorReset)
+	for _, c := range counts[:min(statsTop, len(counts))] {
+		fmt.Printf("  %4d  %s\n", c.Count, c.Name)
+	}
+}
+
+// printUnused prints a line of never-opened config entries
+func printUnused(label string, names []string) {
+	if len(names) > 0 {
+		fmt.Printf("%s  %-10s %s%s\n", colorDim, label+":", colorReset, strings.Join(names, ", "))
+	}
+}
@@ -0,0 +1,361 @@
+// cmd/gcp/use.go
+package gcp
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/itsiqbal/sun-cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// kubeContext records the gcloud project and kubectl context in effect
+type kubeContext struct {
+	GcloudProject string `json:"gcloudProject"`
+	KubeContext   string `json:"kubeContext"`
+}
+
+// useState is the record kept so `sun gcp use -` can switch back
+type useState struct {
+	Previous kubeContext `json:"previous"`
+}
+
+var (
+	// Use flags
+	useDryRun bool
+)
+
+// useCmd represents the gcp use command
+var useCmd = &cobra.Command{
+	Use:   "use <project> <env> | -",
+	Short: "Switch the gcloud project and kubectl context to a project environment",
+	Long: `Resolves a project and environment like the launcher does, then updates the
+active gcloud configuration's core/project and the kubeconfig current-context.
+
+The kubectl context comes from the project's "clusters" map in the config
+(environment → context or GKE cluster name). The previous gcloud project and
+context are recorded, so "sun gcp use -" switches back.`,
+	Example: `  sun gcp use air prod
+  sun gcp use air prod --dry-run
+  sun gcp use -`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runUseCommand,
+}
+
+func init() {
+	useCmd.Flags().BoolVarP(&useDryRun, "dry-run", "n", false, "Show the changes without applying them")
+
+	GcpCmd.AddCommand(useCmd)
+}
+
+// runUseCommand resolves the target and switches gcloud and kubectl to it
+func runUseCommand(cmd *cobra.Command, args []string) error {
+	current := kubeContext{
+		GcloudProject: gcloudProject(),
+		KubeContext:   kubeCurrentContext(),
+	}
+
+	var target kubeContext
+	if args[0] == "-" {
+		if len(args) > 1 {
+			return fmt.Errorf("'-' takes no environment")
+		}
+		state, err := loadUseState()
+		if err != nil || (state.Previous.GcloudProject == "" && state.Previous.KubeContext == "") {
+			return fmt.Errorf("no previous selection recorded")
+		}
+		target = state.Previous
+		fmt.Printf("%s🔄 Switching back...%s\n", colorYellow, colorReset)
+	} else {
+		if len(args) < 2 {
+			return fmt.Errorf("missing environment. Usage: sun gcp use <project> <env>")
+		}
+		project, err := selectProject(args[0], "")
+		if err != nil {
+			return err
+		}
+		env, err := selectEnvironment(project, args[1], "")
+		if err != nil {
+			return err
+		}
+
+		target.GcloudProject = projectID(project, env)
+		if cluster, ok := project.Clusters[env]; ok {
+			target.KubeContext, err = resolveKubeContext(target.GcloudProject, cluster)
+			if err != nil {
+				return err
+			}
+		} else {
+			fmt.Printf("%sNo cluster configured for %s/%s; leaving kubectl context unchanged%s\n",
+				colorDim, project.Name, env, colorReset)
+		}
+	}
+
+	title := "⎈ Context switch"
+	if useDryRun {
+		title += " (dry run)"
+	}
+	fmt.Printf("\n%s%s%s%s\n", colorBold, colorBlue, title, colorReset)
+	printChange("gcloud project", current.GcloudProject, target.GcloudProject)
+	if target.KubeContext != "" {
+		printChange("kubectl context", current.KubeContext, target.KubeContext)
+	}
+	fmt.Println()
+
+	if useDryRun {
+		return nil
+	}
+
+	if target.GcloudProject != "" && target.GcloudProject != current.GcloudProject {
+		if err := setGcloudProject(target.GcloudProject); err != nil {
+			return err
+		}
+	}
+	if target.KubeContext != "" && target.KubeContext != current.KubeContext {
+		if err := setKubeCurrentContext(target.KubeContext); err != nil {
+			return err
+		}
+	}
+
+	if err := saveUseState(useState{Previous: current}); err != nil {
+		fmt.Printf("⚠️ Could not record previous context: %v\n", err)
+	}
+
+	fmt.Printf("%s✓ Switched%s\n", colorGreen, colorReset)
+	return nil
+}
+
+// printChange prints a single "from → to" line
+func printChange(label, from, to string) {
+	if from == "" {
+		from = "(unset)"
+	}
+	if from == to {
+		fmt.Printf("%s  %-16s %s (unchanged)%s\n", colorDim, label+":", to, colorReset)
+		return
+	}
+	fmt.Printf("%s  %-16s %s%s → %s%s%s\n", colorDim, label+":", from, colorReset, colorGreen, to, colorReset)
+}
+
+// loadUseState loads the recorded previous context
+func loadUseState() (*useState, error) {
+	var state useState
+	if err := store.ReadJSON(useStateFile, &state); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// saveUseState records the previous context
+func saveUseState(state useState) error {
+	return store.WriteJSON(useStateFile, state, 0644)
+}
+
+// gcloudConfigDir returns the gcloud configuration directory
+func gcloudConfigDir() string {
+	if dir := os.Getenv("CLOUDSDK_CONFIG"); dir != "" {
+		return dir
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "gcloud")
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "gcloud")
+}
+
+// gcloudConfigFile returns the properties file of the active gcloud configuration
+func gcloudConfigFile() string {
+	dir := gcloudConfigDir()
+
+	name := os.Getenv("CLOUDSDK_ACTIVE_CONFIG_NAME")
+	if name == "" {
+		if data, err := os.ReadFile(filepath.Join(dir, "active_config")); err == nil {
+			name = strings.TrimSpace(string(data))
+		}
+	}
+	if name == "" {
+		name = "default"
+	}
+
+	return filepath.Join(dir, "configurations", "config_"+name)
+}
+
+// gcloudProject returns core/project of the active gcloud configuration
+func gcloudProject() string {
+	data, err := os.ReadFile(gcloudConfigFile())
+	if err != nil {
+		return ""
+	}
+
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.Trim(line, "[]")
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && section == "core" && strings.TrimSpace(key) == "project" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// setGcloudProject sets core/project in the active gcloud configuration,
+// keeping every other line of the properties file as it is
+func setGcloudProject(project string) error {
+	path := gcloudConfigFile()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read gcloud config: %w", err)
+	}
+
+	var out []string
+	section, done := "", false
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			section = strings.Trim(trimmed, "[]")
+			out = append(out, line)
+			if section == "core" && !done {
+				out = append(out, "project = "+project)
+				done = true
+			}
+			continue
+		}
+		if key, _, ok := strings.Cut(trimmed, "="); ok && section == "core" && strings.TrimSpace(key) == "project" {
+			continue // replaced above
+		}
+		out = append(out, line)
+	}
+	if !done {
+		out = append(out, "[core]", "project = "+project)
+	}
+
+	// gcloud owns the file, so no sidecar lock next to it
+	if err := store.Replace(path, []byte(strings.Join(out, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write gcloud config: %w", err)
+	}
+	return nil
+}
+
+// kubeconfigFile returns the kubeconfig that holds current-context
+func kubeconfigFile() string {
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		return filepath.SplitList(env)[0]
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".kube", "config")
+}
+
+// readKubeconfig returns the kubeconfig lines
+func readKubeconfig() ([]string, error) {
+	data, err := os.ReadFile(kubeconfigFile())
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(data), "\n"), nil
+}
+
+// kubeCurrentContext returns the kubeconfig current-context
+func kubeCurrentContext() string {
+	lines, err := readKubeconfig()
+	if err != nil {
+		return ""
+	}
+	for _, line := range lines {
+		if value, ok := strings.CutPrefix(line, "current-context:"); ok {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// kubeContexts returns the context names defined in the kubeconfig
+func kubeContexts() []string {
+	lines, err := readKubeconfig()
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	inContexts := false
+	for _, line := range lines {
+		// A top-level key starts or ends the contexts block
+		if line != "" && line[0] != ' ' && line[0] != '-' && line[0] != '#' {
+			inContexts = strings.HasPrefix(line, "contexts:")
+			continue
+		}
+		if !inContexts {
+			continue
+		}
+		// Context names sit at item level: "- name: x" or "  name: x"
+		trimmed := strings.TrimPrefix(line, "-")
+		indent := len(trimmed) - len(strings.TrimLeft(trimmed, " "))
+		if indent > 2 {
+			continue
+		}
+		if value, ok := strings.CutPrefix(strings.TrimSpace(trimmed), "name:"); ok {
+			names = append(names, strings.Trim(strings.TrimSpace(value), `"'`))
+		}
+	}
+	return names
+}
+
+// resolveKubeContext finds the kubeconfig context for a configured cluster,
+// either by exact context name or by GKE cluster name (gke_<project>_<location>_<cluster>)
+func resolveKubeContext(gcpProject, cluster string) (string, error) {
+	contexts := kubeContexts()
+	for _, name := range contexts {
+		if name == cluster {
+			return name, nil
+		}
+	}
+	prefix := "gke_" + gcpProject + "_"
+	for _, name := range contexts {
+		if strings.HasPrefix(name, prefix) && strings.HasSuffix(name, "_"+cluster) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("no kubectl context for cluster '%s' in %s. Run: gcloud container clusters get-credentials %s --project %s",
+		cluster, kubeconfigFile(), cluster, gcpProject)
+}
+
+// setKubeCurrentContext rewrites the current-context line of the kubeconfig,
+// keeping the rest of the file untouched
+func setKubeCurrentContext(name string) error {
+	lines, err := readKubeconfig()
+	if err != nil {
+		return fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+
+	replaced := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "current-context:") {
+			lines[i] = "current-context: " + name
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		lines = append([]string{"current-context: " + name}, lines...)
+	}
+
+	// No store.Lock here: kubectl takes "<kubeconfig>.lock" as its own
+	// O_EXCL lock and fails while such a file exists
+	if err := store.Replace(kubeconfigFile(), []byte(strings.Join(lines, "\n")), 0600); err != nil {
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+// cmd/gcp/which.go
+package gcp
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+const consoleHost = "console.cloud.google.com"
+
+var (
+	// Which flags
+	whichBookmark string
+	whichNoPrompt bool
+)
+
+// whichResult holds what a console URL resolved to
+type whichResult struct {
+	ProjectParam string
+	Path         string
+	Project      *Project
+	Env          string
+	Service      *Service
+}
+
+// whichCmd represents the gcp which command
+var whichCmd = &cobra.Command{
+	Use:   "which <url>",
+	Short: "Resolve a console URL back to a configured project, environment and service",
+	Long: `Parses a console.cloud.google.com URL, resolves the project= parameter back
+to a configured project/environment and the path to a configured service.
+
+Unknown projects or paths can be added to the config interactively, and the
+link can be saved as a named bookmark.`,
+	Example: `  sun gcp which "https://console.cloud.google.com/logs/query?project=airasia-oms-prd"
+  sun gcp which "<url>" --bookmark "OMS prod errors"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWhichCommand,
+}
+
+func init() {
+	whichCmd.Flags().StringVarP(&whichBookmark, "bookmark", "b", "", "Save the URL as a bookmark with this name")
+	whichCmd.Flags().BoolVar(&whichNoPrompt, "no-prompt", false, "Never offer to add unknown projects or paths")
+
+	GcpCmd.AddCommand(whichCmd)
+}
+
+// runWhichCommand resolves a console URL and offers to extend the config
+func runWhichCommand(cmd *cobra.Command, args []string) error {
+	result, err := resolveConsoleURL(args[0])
+	if err != nil {
+		return err
+	}
+
+	printWhichResult(result)
+
+	// Prompts come first; the edits are then applied to the latest config
+	var edits []func(cfg *Config)
+	interactive := !whichNoPrompt && isTerminal(os.Stdin)
+
+	if result.Project == nil && result.ProjectParam != "" && interactive {
+		edit, err := offerAddProject(result)
+		if err != nil {
+			return err
+		}
+		if edit != nil {
+			edits = append(edits, edit)
+		}
+	}
+
+	if result.Service == nil && result.Path != "" && interactive {
+		edit, err := offerAddService(result)
+		if err != nil {
+			return err
+		}
+		if edit != nil {
+			edits = append(edits, edit)
+		}
+	}
+
+	if whichBookmark != "" {
+		bookmark := Bookmark{Name: whichBookmark, URL: args[0]}
+		edits = append(edits, func(cfg *Config) {
+			cfg.Bookmarks = append(cfg.Bookmarks, bookmark)
+			fmt.Printf("%s✓ Saved bookmark:%s %s\n", colorGreen, colorReset, bookmark.Name)
+		})
+	}
+
+	if len(edits) > 0 {
+		err := updateConfig(func(cfg *Config) {
+			for _, edit := range edits {
+				edit(cfg)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%sConfig updated: %s%s\n", colorDim, configFile, colorReset)
+	}
+
+	return nil
+}
+
+// resolveConsoleURL parses a console URL and matches it against the config
+func resolveConsoleURL(raw string) (*whichResult, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if !strings.EqualFold(u.Hostname(), consoleHost) {
+		return nil, fmt.Errorf("not a %s URL: %s", consoleHost, raw)
+	}
+
+	result := &whichResult{
+		ProjectParam: u.Query().Get("project"),
+		Path:         strings.Trim(u.Path, "/"),
+	}
+
+	result.Project, result.Env = findProjectByID(result.ProjectParam)
+	result.Service = findServiceByPath(result.Path)
+
+	return result, nil
+}
+
+// findProjectByID finds the project and environment whose combined ID matches
+func findProjectByID(id string) (*Project, string) {
+	if id == "" {
+		return nil, ""
+	}
+	for i := range config.Projects {
+		for _, env := range config.Projects[i].Environments {
+			if strings.EqualFold(projectID(&config.Projects[i], env), id) {
+				return &config.Projects[i], env
+			}
+		}
+	}
+	return nil, ""
+}
+
+// findServiceByPath finds the service with the longest path matching the URL path
+func findServiceByPath(path string) *Service {
+	// Matrix parameters such as ";timeRange=PT1H" are not part of the page
+	path, _, _ = strings.Cut(path, ";")
+
+	var best *Service
+	bestLen := 0
+	for i := range config.Services {
+		servicePath, _, _ := strings.Cut(strings.Trim(config.Services[i].Path, "/"), ";")
+		if servicePath == "" {
+			continue
+		}
+		if path != servicePath && !strings.HasPrefix(path, servicePath+"/") {
+			continue
+		}
+		if len(servicePath) > bestLen {
+			best, bestLen = &config.Services[i], len(servicePath)
+		}
+	}
+	return best
+}
+
+// printWhichResult prints what the URL resolved to
+func printWhichResult(r *whichResult) {
+	fmt.Printf("\n%s%s🔎 Console URL%s\n", colorBold, colorBlue, colorReset)
+
+	switch {
+	case r.Project != nil:
+		fmt.Printf("%s  Project:     %s%s %s(%s)%s\n", colorDim, colorReset, r.Project.Name, colorDim, r.Project.ID, colorReset)
+		fmt.Printf("%s  Environment: %s%s\n", colorDim, colorReset, r.Env)
+	case r.ProjectParam != "":
+		fmt.Printf("%s  Project:     %s%s%s (not in config)%s\n", colorDim, colorReset, r.ProjectParam, colorYellow, colorReset)
+	default:
+		fmt.Printf("%s  Project:     %s%s(no project parameter)%s\n", colorDim, colorReset, colorYellow, colorReset)
+	}
+
+	switch {
+	case r.Service != nil:
+		fmt.Printf("%s  Service:     %s%s %s(%s)%s\n", colorDim, colorReset, r.Service.Name, colorDim, r.Path, colorReset)
+	case r.Path != "":
+		fmt.Printf("%s  Service:     %s%s%s (not in config)%s\n", colorDim, colorReset, r.Path, colorYellow, colorReset)
+	default:
+		fmt.Printf("%s  Service:     %s%s(console home)%s\n", colorDim, colorReset, colorYellow, colorReset)
+	}
+	fmt.Println()
+}
+
+// offerAddProject asks to add an unknown project ID to the config and
+// returns the edit that adds it, or nil when declined
+func offerAddProject(r *whichResult) (func(cfg *Config), error) {
+	if !confirm(fmt.Sprintf("Add project '%s' to config", r.ProjectParam)) {
+		return nil, nil
+	}
+
+	// Split "<id>-<env>" on the last dash as a starting suggestion
+	id := r.ProjectParam
+	if i := strings.LastIndex(r.ProjectParam, "-"); i > 0 {
+		id = r.ProjectParam[:i]
+	}
+
+	id, err := promptText("Project ID (without environment suffix)", id)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(r.ProjectParam, id+"-") {
+		return nil, fmt.Errorf("'%s' is not a prefix of '%s'", id, r.ProjectParam)
+	}
+	env := strings.TrimPrefix(r.ProjectParam, id+"-")
+
+	// A project with the same ID only needs the environment, so no name
+	name := ""
+	if findProjectByIDPrefix(id) == nil {
+		if name, err = promptText("Project name", id); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(cfg *Config) {
+		// Extend an existing project with the same ID
+		for i := range cfg.Projects {
+			if cfg.Projects[i].ID == id {
+				if !containsFold(cfg.Projects[i].Environments, env) {
+					cfg.Projects[i].Environments = append(cfg.Projects[i].Environments, env)
+				}
+				fmt.Printf("%s✓ Added environment '%s' to %s%s\n", colorGreen, env, cfg.Projects[i].Name, colorReset)
+				return
+			}
+		}
+		if name == "" {
+			name = id
+		}
+		cfg.Projects = append(cfg.Projects, Project{Name: name, ID: id, Environments: []string{env}})
+		fmt.Printf("%s✓ Added project %s (%s) with environment '%s'%s\n", colorGreen, name, id, env, colorReset)
+	}, nil
+}
+
+// findProjectByIDPrefix finds a project by its exact ID, without environment
+func findProjectByIDPrefix(id string) *Project {
+	for i := range config.Projects {
+		if config.Projects[i].ID == id {
+			return &config.Projects[i]
+		}
+	}
+	return nil
+}
+
+// offerAddService asks to add an unknown console path as a service and
+// returns the edit that adds it, or nil when declined
+func offerAddService(r *whichResult) (func(cfg *Config), error) {
+	if !confirm(fmt.Sprintf("Add path '%s' as a service", r.Path)) {
+		return nil, nil
+	}
+
+	name, err := promptText("Service name", r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	service := Service{Name: name, Path: r.Path}
+	return func(cfg *Config) {
+		cfg.Services = append(cfg.Services, service)
+		fmt.Printf("%s✓ Added service %s → %s%s\n", colorGreen, name, r.Path, colorReset)
+	}, nil
+}
+
+// confirm asks a yes/no question, defaulting to no
+func confirm(label string) bool {
+	prompt := promptui.Prompt{
+		Label:     label,
+		IsConfirm: true,
+		Stdout:    &bellSkipper{},
+	}
+	_, err := prompt.Run()
+	return err == nil
+}
+
+// promptText asks for a non-empty line of text with a default value
+func promptText(label, defaultValue string) (string, error) {
+	prompt := promptui.Prompt{
+		Label:     label,
+		Default:   defaultValue,
+		AllowEdit: true,
+		Stdout:    &bellSkipper{},
+		Validate: func(input string) error {
+			if strings.TrimSpace(input) == "" {
+				return fmt.Errorf("value cannot be empty")
+			}
+			return nil
+		},
+	}
+	result, err := prompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("input cancelled: %w", err)
+	}
+	return strings.TrimSpace(result), nil
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,184 @@
+// cmd/gcp/wizard.go
+package gcp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/manifoldco/promptui"
+)
+
+var (
+	// errGoBack is returned by a step when the user picks "← Go Back"
+	errGoBack = errors.New("go back")
+
+	// errCancelled is returned when the user cancels a prompt with Esc, Ctrl-C or Ctrl-D
+	errCancelled = errors.New("selection cancelled")
+)
+
+// wizardStep identifies a step of the selection wizard
+type wizardStep int
+
+const (
+	stepProject wizardStep = iota
+	stepEnvironment
+	stepService
+	stepDone
+)
+
+// wizardOut receives the messages of the selection steps; sun gcp cli
+// points it at stderr to keep stdout for the commands
+var wizardOut io.Writer = os.Stdout
+
+// selection holds the answers collected by the wizard
+type selection struct {
+	Project *Project
+	Env     string
+	Service *Service
+}
+
+// wizard walks project → environment → service as a state machine.
+// Every interactive step can go back to the previous one, and pre-filled
+// answers (from flags, arguments or the cache) are used once each: going
+// back to a step always shows its prompt, with the last answer under the cursor.
+type wizard struct {
+	prefill map[wizardStep]string
+	sel     selection
+
+	// useDefaultService opens the environment's default service when only
+	// project and environment were given
+	useDefaultService bool
+
+	// prompted is set once any step asks interactively
+	prompted bool
+}
+
+// newWizard creates a wizard with optional pre-filled answers
+func newWizard(project, env, service string) *wizard {
+	return &wizard{
+		prefill: map[wizardStep]string{
+			stepProject:     project,
+			stepEnvironment: env,
+			stepService:     service,
+		},
+		useDefaultService: project != "" && env != "" && service == "",
+	}
+}
+
+// run drives the wizard until every step is answered or the user cancels
+func (w *wizard) run() (*selection, error) {
+	step := stepProject
+	for step != stepDone {
+		filter := w.prefill[step]
+		delete(w.prefill, step)
+
+		err := w.runStep(step, filter)
+		switch {
+		case err == nil:
+			step++
+		case errors.Is(err, errGoBack):
+			if step > stepProject {
+				step--
+			}
+		case errors.Is(err, errCancelled):
+			return nil, err
+		case filter != "":
+			// The pre-filled answer did not match (the step already listed
+			// the valid options); ask interactively instead
+			continue
+		default:
+			return nil, err
+		}
+	}
+	return &w.sel, nil
+}
+
+// runStep runs a single step, storing its answer on success
+func (w *wizard) runStep(step wizardStep, filter string) error {
+	if step == stepService && w.useDefaultService {
+		w.useDefaultService = false
+		if service := findDefaultService(w.sel.Project, w.sel.Env); service != nil {
+			fmt.Fprintf(wizardOut, "%s✓ Default service:%s %s\n", colorGreen, colorReset, service.Name)
+			w.sel.Service = service
+			return nil
+		}
+	}
+
+	// Without a pre-filled answer the step asks interactively
+	if filter == "" {
+		w.prompted = true
+	}
+
+	switch step {
+	case stepProject:
+		current := ""
+		if w.sel.Project != nil {
+			current = w.sel.Project.Name
+		}
+		project, err := selectProject(filter, current)
+		if err != nil {
+			return err
+		}
+		w.sel.Project = project
+	case stepEnvironment:
+		env, err := selectEnvironment(w.sel.Project, filter, w.sel.Env)
+		if err != nil {
+			return err
+		}
+		w.sel.Env = env
+	case stepService:
+		current := ""
+		if w.sel.Service != nil {
+			current = w.sel.Service.Name
+		}
+		service, err := selectService(w.sel.Project, w.sel.Env, filter, current)
+		if err != nil {
+			return err
+		}
+		w.sel.Service = service
+	}
+	return nil
+}
+
+// promptError maps promptui cancellation to errCancelled
+func promptError(what string, err error) error {
+	if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
+		return errCancelled
+	}
+	return fmt.Errorf("%s selection failed: %w", what, err)
+}
+
+// escCanceller implements an io.ReadCloser over stdin that turns a lone Esc
+// key press into Ctrl-C, so prompts can be cancelled with Esc. Escape
+// sequences such as arrow keys arrive in a single read and pass through.
+type escCanceller struct{}
+
+// Read implements io.Reader by translating a lone Esc into an interrupt
+func (ec *escCanceller) Read(b []byte) (int, error) {
+	const (
+		charEsc       = 27 // Escape character
+		charInterrupt = 3  // Ctrl-C
+	)
+	n, err := os.Stdin.Read(b)
+	if n == 1 && b[0] == charEsc {
+		b[0] = charInterrupt
+	}
+	return n, err
+}
+
+// Close implements io.Closer
+func (ec *escCanceller) Close() error {
+	return nil
+}
+
+// indexOf returns the index of s in items, or -1
+func indexOf(items []string, s string) int {
+	for i, item := range items {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,87 @@
+// Package llm puts chat model servers behind one Provider interface, so
+// commands can talk to Ollama or any OpenAI-compatible endpoint (llama.cpp
+// server, vLLM, LM Studio, hosted APIs) the same way.
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Provider names
+const (
+	ProviderOllama = "ollama"
+	ProviderOpenAI = "openai"
+)
+
+// Providers lists the supported provider names
+var Providers = []string{ProviderOllama, ProviderOpenAI}
+
+// Message roles
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message is one turn of a conversation
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request is a chat completion request
+type Request struct {
+	Model    string
+	Messages []Message
+	JSON     bool // ask for a reply that is a single JSON object
+}
+
+// Response is a complete assistant reply
+type Response struct {
+	Model   string
+	Content string
+}
+
+// Provider is a chat model server
+type Provider interface {
+	// Name returns the provider name, e.g. "ollama"
+	Name() string
+	// Chat sends the conversation and returns the assistant's reply
+	Chat(ctx context.Context, req Request) (*Response, error)
+	// ChatStream is Chat with the reply streamed: onToken is called with each
+	// piece of text as it arrives, and the whole reply is returned at the end.
+	// Canceling ctx stops the stream.
+	ChatStream(ctx context.Context, req Request, onToken func(string) error) (*Response, error)
+}
+
+// Config selects and configures a provider
+type Config struct {
+	Provider string // ProviderOllama or ProviderOpenAI
+	BaseURL  string // server URL; empty for the provider's default
+	APIKey   string // bearer token, for servers that need one
+
+	HTTPClient *http.Client // nil for http.DefaultClient
+}
+
+// New returns the provider named in cfg
+func New(cfg Config) (Provider, error) {
+	switch strings.ToLower(cfg.Provider) {
+	case ProviderOllama, "":
+		p := NewOllama(cfg.BaseURL)
+		if cfg.HTTPClient != nil {
+			p.Client.HTTPClient = cfg.HTTPClient
+		}
+		return p, nil
+	case ProviderOpenAI:
+		p := NewOpenAI(cfg.BaseURL, cfg.APIKey)
+		if cfg.HTTPClient != nil {
+			p.HTTPClient = cfg.HTTPClient
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unknown provider '%s'. Valid: %s", cfg.Provider, strings.Join(Providers, ", "))
+	}
+}
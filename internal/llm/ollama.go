@@ -0,0 +1,56 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/itsiqbal/sun-cli/internal/ollama"
+)
+
+// Ollama is a Provider backed by an Ollama server
+type Ollama struct {
+	Client *ollama.Client
+}
+
+// NewOllama returns a provider for the Ollama server at baseURL, or the
+// local default when empty
+func NewOllama(baseURL string) *Ollama {
+	return &Ollama{Client: ollama.New(baseURL)}
+}
+
+func (o *Ollama) Name() string {
+	return ProviderOllama
+}
+
+func (o *Ollama) Chat(ctx context.Context, req Request) (*Response, error) {
+	resp, err := o.Client.Chat(ctx, ollamaRequest(req))
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Model: resp.Model, Content: resp.Message.Content}, nil
+}
+
+func (o *Ollama) ChatStream(ctx context.Context, req Request, onToken func(string) error) (*Response, error) {
+	resp, err := o.Client.ChatStream(ctx, ollamaRequest(req), func(chunk ollama.ChatResponse) error {
+		if chunk.Message.Content == "" {
+			return nil
+		}
+		return onToken(chunk.Message.Content)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Model: resp.Model, Content: resp.Message.Content}, nil
+}
+
+// ollamaRequest converts a request to the Ollama API types
+func ollamaRequest(req Request) ollama.ChatRequest {
+	messages := make([]ollama.Message, len(req.Messages))
+	for i, m := range req.Messages {
+		messages[i] = ollama.Message{Role: m.Role, Content: m.Content}
+	}
+	out := ollama.ChatRequest{Model: req.Model, Messages: messages}
+	if req.JSON {
+		out.Format = "json"
+	}
+	return out
+}
@@ -0,0 +1,247 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultOpenAIBaseURL is used when no base URL is configured
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAI is a Provider for servers implementing the OpenAI chat completions
+// API: POST {BaseURL}/chat/completions. The base URL includes the version
+// prefix, e.g. http://localhost:8080/v1 for a llama.cpp server.
+type OpenAI struct {
+	BaseURL    string
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// openAIRequest is the body of POST /chat/completions
+type openAIRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+	Stream   bool      `json:"stream"`
+	// ResponseFormat is {"type":"json_object"} for a JSON reply
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+}
+
+type openAIResponseFormat struct {
+	Type string `json:"type"`
+}
+
+// newOpenAIRequest converts a request to the chat completions body
+func newOpenAIRequest(req Request, stream bool) openAIRequest {
+	out := openAIRequest{Model: req.Model, Messages: req.Messages, Stream: stream}
+	if req.JSON {
+		out.ResponseFormat = &openAIResponseFormat{Type: "json_object"}
+	}
+	return out
+}
+
+// openAIResponse is the part of a chat completion reply we use
+type openAIResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message      Message `json:"message"`
+		FinishReason string  `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// openAIChunk is one server-sent event of a streamed chat completion
+type openAIChunk struct {
+	Error   json.RawMessage `json:"error"` // some servers report failures mid-stream
+	Model   string          `json:"model"`
+	Choices []struct {
+		Delta        Message `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// NewOpenAI returns a provider for the OpenAI-compatible server at baseURL,
+// or api.openai.com when empty. apiKey may be empty for local servers.
+func NewOpenAI(baseURL, apiKey string) *OpenAI {
+	if baseURL == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
+	return &OpenAI{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		APIKey:     apiKey,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+func (o *OpenAI) Name() string {
+	return ProviderOpenAI
+}
+
+func (o *OpenAI) Chat(ctx context.Context, req Request) (*Response, error) {
+	data, err := json.Marshal(newOpenAIRequest(req, false))
+	if err != nil {
+		return nil, fmt.Errorf("openai: failed to encode request: %w", err)
+	}
+
+	resp, err := o.post(ctx, "/chat/completions", data)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("openai: invalid response: %w", err)
+	}
+	if len(out.Choices) == 0 {
+		return nil, fmt.Errorf("openai: response has no choices")
+	}
+	return &Response{Model: out.Model, Content: out.Choices[0].Message.Content}, nil
+}
+
+// ChatStream streams the reply as server-sent events: "data: {chunk}" lines,
+// each chunk carrying a piece of the message in choices[0].delta, ending with
+// "data: [DONE]"
+func (o *OpenAI) ChatStream(ctx context.Context, req Request, onToken func(string) error) (*Response, error) {
+	data, err := json.Marshal(newOpenAIRequest(req, true))
+	if err != nil {
+		return nil, fmt.Errorf("openai: failed to encode request: %w", err)
+	}
+
+	resp, err := o.post(ctx, "/chat/completions", data)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	out := &Response{Model: req.Model}
+	var content strings.Builder
+	done := false
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		// Comments (":"), event names and blank separators carry no data
+		payload, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		payload = strings.TrimSpace(payload)
+		if payload == "[DONE]" {
+			done = true
+			break
+		}
+
+		var chunk openAIChunk
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			return nil, fmt.Errorf("openai: invalid stream chunk: %w", err)
+		}
+		// Some compatible servers send "error": null on every chunk
+		if len(chunk.Error) > 0 && !bytes.Equal(chunk.Error, []byte("null")) {
+			return nil, &APIError{StatusCode: resp.StatusCode, Message: openAIErrorMessage([]byte(payload))}
+		}
+		if chunk.Model != "" {
+			out.Model = chunk.Model
+		}
+		for _, choice := range chunk.Choices[:min(1, len(chunk.Choices))] {
+			if choice.Delta.Content != "" {
+				if err := onToken(choice.Delta.Content); err != nil {
+					return nil, err
+				}
+				content.WriteString(choice.Delta.Content)
+			}
+			if choice.FinishReason != nil {
+				done = true
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("openai: failed to read stream: %w", err)
+	}
+	if !done {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("openai: stream ended before the reply was done")
+	}
+
+	out.Content = content.String()
+	return out, nil
+}
+
+// post sends a JSON body and returns the response, or an *APIError for an
+// error status
+func (o *OpenAI) post(ctx context.Context, path string, data []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.BaseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("openai: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.APIKey)
+	}
+
+	client := o.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai: request failed: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return nil, readOpenAIError(resp)
+	}
+	return resp, nil
+}
+
+// APIError is an error status returned by an OpenAI-compatible server
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("openai: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("openai: %s (%d)", e.Message, e.StatusCode)
+}
+
+// readOpenAIError builds an APIError from an error response
+func readOpenAIError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	return &APIError{StatusCode: resp.StatusCode, Message: openAIErrorMessage(data)}
+}
+
+// openAIErrorMessage extracts the message of an error body. OpenAI sends
+// {"error": {"message": "..."}}; some compatible servers send
+// {"error": "..."}, and anything else is kept as plain text.
+func openAIErrorMessage(data []byte) string {
+	message := strings.TrimSpace(string(data))
+
+	var body struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(data, &body) == nil && len(body.Error) > 0 {
+		var detail struct {
+			Message string `json:"message"`
+		}
+		var text string
+		switch {
+		case json.Unmarshal(body.Error, &detail) == nil && detail.Message != "":
+			message = detail.Message
+		case json.Unmarshal(body.Error, &text) == nil && text != "":
+			message = text
+		}
+	}
+	return message
+}
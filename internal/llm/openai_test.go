@@ -0,0 +1,274 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeOpenAI answers /v1/chat/completions with handler after checking the
+// request method and path
+func fakeOpenAI(t *testing.T, apiKey string, handler func(w http.ResponseWriter, r *http.Request, req openAIRequest)) *OpenAI {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("got %s %s, want POST /v1/chat/completions", r.Method, r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("request body is not valid JSON: %v", err)
+		}
+		handler(w, r, req)
+	}))
+	t.Cleanup(srv.Close)
+	return NewOpenAI(srv.URL+"/v1/", apiKey)
+}
+
+func TestOpenAIChat(t *testing.T) {
+	client := fakeOpenAI(t, "", func(w http.ResponseWriter, r *http.Request, req openAIRequest) {
+		if req.Model != "gpt-4o-mini" || req.Stream {
+			t.Errorf("model = %q, stream = %v; want gpt-4o-mini without streaming", req.Model, req.Stream)
+		}
+		if len(req.Messages) != 2 || req.Messages[0].Role != RoleSystem || req.Messages[1].Content != "hi" {
+			t.Errorf("messages = %+v, want the system and user messages", req.Messages)
+		}
+		if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_object" {
+			t.Errorf("response_format = %+v, want json_object", req.ResponseFormat)
+		}
+		w.Write([]byte(`{
+			"model": "gpt-4o-mini-2024-07-18",
+			"choices": [
+				{"message": {"role": "assistant", "content": "{\"ok\": true}"}, "finish_reason": "stop"},
+				{"message": {"role": "assistant", "content": "second"}, "finish_reason": "stop"}
+			]
+		}`))
+	})
+
+	resp, err := client.Chat(context.Background(), Request{
+		Model:    "gpt-4o-mini",
+		Messages: []Message{{Role: RoleSystem, Content: "be brief"}, {Role: RoleUser, Content: "hi"}},
+		JSON:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Model != "gpt-4o-mini-2024-07-18" || resp.Content != `{"ok": true}` {
+		t.Errorf("response = %+v, want the first choice and the served model", resp)
+	}
+}
+
+func TestOpenAIChatNoChoices(t *testing.T) {
+	client := fakeOpenAI(t, "", func(w http.ResponseWriter, r *http.Request, req openAIRequest) {
+		w.Write([]byte(`{"model": "m", "choices": []}`))
+	})
+
+	if _, err := client.Chat(context.Background(), Request{Model: "m"}); err == nil {
+		t.Error("err = nil, want an error for a reply without choices")
+	}
+}
+
+func TestOpenAIAuthorization(t *testing.T) {
+	tests := []struct {
+		name   string
+		apiKey string
+		want   string
+	}{
+		{"with key", "sk-test", "Bearer sk-test"},
+		{"without key", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fakeOpenAI(t, tt.apiKey, func(w http.ResponseWriter, r *http.Request, req openAIRequest) {
+				if got := r.Header.Get("Authorization"); got != tt.want {
+					t.Errorf("Authorization = %q, want %q", got, tt.want)
+				}
+				w.Write([]byte(`{"choices": [{"message": {"content": "ok"}}]}`))
+			})
+			if _, err := client.Chat(context.Background(), Request{Model: "m"}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestOpenAIErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantMessage string
+		wantError   string
+	}{
+		{
+			name:        "OpenAI error object",
+			status:      http.StatusUnauthorized,
+			body:        `{"error": {"message": "Incorrect API key provided", "type": "invalid_request_error"}}`,
+			wantMessage: "Incorrect API key provided",
+			wantError:   "openai: Incorrect API key provided (401)",
+		},
+		{
+			name:        "error string",
+			status:      http.StatusNotFound,
+			body:        `{"error": "model 'x' not found"}`,
+			wantMessage: "model 'x' not found",
+			wantError:   "openai: model 'x' not found (404)",
+		},
+		{
+			name:        "plain text",
+			status:      http.StatusBadGateway,
+			body:        "upstream unavailable\n",
+			wantMessage: "upstream unavailable",
+			wantError:   "openai: upstream unavailable (502)",
+		},
+		{
+			name:        "empty body",
+			status:      http.StatusServiceUnavailable,
+			body:        "",
+			wantMessage: "",
+			wantError:   "openai: 503 Service Unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fakeOpenAI(t, "", func(w http.ResponseWriter, r *http.Request, req openAIRequest) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := client.Chat(context.Background(), Request{Model: "m"})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Message != tt.wantMessage {
+				t.Errorf("APIError = %+v, want status %d and message %q", apiErr, tt.status, tt.wantMessage)
+			}
+			if err.Error() != tt.wantError {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.wantError)
+			}
+		})
+	}
+}
+
+// serveSSE writes the lines as a streamed reply, flushing after each one
+func serveSSE(t *testing.T, lines ...string) *OpenAI {
+	t.Helper()
+	return fakeOpenAI(t, "", func(w http.ResponseWriter, r *http.Request, req openAIRequest) {
+		if !req.Stream {
+			t.Error("stream = false, want true")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, line := range lines {
+			w.Write([]byte(line + "\n"))
+			w.(http.Flusher).Flush()
+		}
+	})
+}
+
+func TestOpenAIChatStream(t *testing.T) {
+	client := serveSSE(t,
+		": keep-alive",
+		"",
+		`data: {"model":"gpt-4o-mini-2024-07-18","choices":[{"delta":{"role":"assistant","content":""}}]}`,
+		"",
+		"event: message",
+		`data: {"choices":[{"delta":{"content":"Hel"}}],"error":null}`,
+		"",
+		": ping",
+		`data:{"choices":[{"delta":{"content":"lo"}}]}`,
+		"",
+		`data: {"choices":[{"delta":{},"finish_reason":"stop"}]}`,
+		"",
+		"data: [DONE]",
+		"",
+	)
+
+	var tokens []string
+	resp, err := client.ChatStream(context.Background(), Request{Model: "gpt-4o-mini"}, func(token string) error {
+		tokens = append(tokens, token)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 2 || tokens[0] != "Hel" || tokens[1] != "lo" {
+		t.Errorf("tokens = %q, want [Hel lo]", tokens)
+	}
+	if resp.Content != "Hello" || resp.Model != "gpt-4o-mini-2024-07-18" {
+		t.Errorf("response = %+v, want Hello from the served model", resp)
+	}
+}
+
+func TestOpenAIChatStreamEnd(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		wantErr bool
+	}{
+		{"DONE without finish_reason", []string{`data: {"choices":[{"delta":{"content":"hi"}}]}`, "data: [DONE]"}, false},
+		{"finish_reason without DONE", []string{`data: {"choices":[{"delta":{"content":"hi"},"finish_reason":"length"}]}`}, false},
+		{"cut off", []string{`data: {"choices":[{"delta":{"content":"hi"}}]}`}, true},
+		{"invalid chunk", []string{`data: {"choices":`}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := serveSSE(t, tt.lines...)
+			_, err := client.ChatStream(context.Background(), Request{Model: "m"}, func(string) error { return nil })
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOpenAIChatStreamMidStreamError(t *testing.T) {
+	client := serveSSE(t,
+		`data: {"choices":[{"delta":{"content":"partial"}}]}`,
+		"",
+		`data: {"error":{"message":"The server is overloaded","type":"server_error"}}`,
+		"",
+	)
+
+	var got string
+	_, err := client.ChatStream(context.Background(), Request{Model: "m"}, func(token string) error {
+		got += token
+		return nil
+	})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *APIError", err)
+	}
+	if apiErr.Message != "The server is overloaded" {
+		t.Errorf("message = %q, want the error from the stream", apiErr.Message)
+	}
+	if got != "partial" {
+		t.Errorf("tokens before the error = %q, want partial", got)
+	}
+}
+
+func TestOpenAIChatStreamTokenError(t *testing.T) {
+	client := serveSSE(t,
+		`data: {"choices":[{"delta":{"content":"a"}}]}`,
+		`data: {"choices":[{"delta":{"content":"b"}}]}`,
+		"data: [DONE]",
+	)
+
+	stop := errors.New("stop")
+	calls := 0
+	_, err := client.ChatStream(context.Background(), Request{Model: "m"}, func(string) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("err = %v after %d calls, want the callback's error after 1", err, calls)
+	}
+}
@@ -0,0 +1,130 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Model is an installed model, as listed by GET /api/tags
+type Model struct {
+	Name       string       `json:"name"`
+	Model      string       `json:"model"`
+	ModifiedAt time.Time    `json:"modified_at"`
+	Size       int64        `json:"size"` // bytes
+	Digest     string       `json:"digest"`
+	Details    ModelDetails `json:"details"`
+}
+
+// ModelDetails describes a model's architecture and quantization
+type ModelDetails struct {
+	Format            string `json:"format"`
+	Family            string `json:"family"`
+	ParameterSize     string `json:"parameter_size"`
+	QuantizationLevel string `json:"quantization_level"`
+}
+
+// ModelInfo is the reply of POST /api/show
+type ModelInfo struct {
+	License    string         `json:"license"`
+	Modelfile  string         `json:"modelfile"`
+	Parameters string         `json:"parameters"`
+	Template   string         `json:"template"`
+	System     string         `json:"system"`
+	Details    ModelDetails   `json:"details"`
+	Info       map[string]any `json:"model_info"`
+	ModifiedAt time.Time      `json:"modified_at"`
+}
+
+// PullProgress is one status update of POST /api/pull. Total and Completed
+// are set while a layer downloads.
+type PullProgress struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	Completed int64  `json:"completed,omitempty"`
+}
+
+// modelRequest names the model for show, pull and delete
+type modelRequest struct {
+	Model  string `json:"model"`
+	Stream *bool  `json:"stream,omitempty"`
+}
+
+// List returns the installed models
+func (c *Client) List(ctx context.Context) ([]Model, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Models []Model `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("ollama: invalid response: %w", err)
+	}
+	return out.Models, nil
+}
+
+// Show returns the details, parameters and prompt template of a model
+func (c *Client) Show(ctx context.Context, name string) (*ModelInfo, error) {
+	var info ModelInfo
+	if err := c.post(ctx, "/api/show", modelRequest{Model: name}, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Pull downloads a model, calling fn with each status update as it arrives.
+// The last update has the status "success".
+func (c *Client) Pull(ctx context.Context, name string, fn func(PullProgress) error) error {
+	stream := true
+	success := false
+	err := c.stream(ctx, http.MethodPost, "/api/pull", modelRequest{Model: name, Stream: &stream}, func(line []byte) (bool, error) {
+		var p PullProgress
+		if err := json.Unmarshal(line, &p); err != nil {
+			return false, fmt.Errorf("ollama: invalid pull status: %w", err)
+		}
+		if err := fn(p); err != nil {
+			return false, err
+		}
+		success = p.Status == "success"
+		return success, nil
+	})
+	if err != nil {
+		return err
+	}
+	if !success {
+		return fmt.Errorf("ollama: pull ended before it succeeded")
+	}
+	return nil
+}
+
+// Delete removes an installed model
+func (c *Client) Delete(ctx context.Context, name string) error {
+	resp, err := c.do(ctx, http.MethodDelete, "/api/delete", modelRequest{Model: name})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// MatchModel finds a model by name in a list. A name without a tag matches
+// the "latest" tag, as Ollama resolves it: "llama3" is "llama3:latest".
+func MatchModel(models []Model, name string) (*Model, bool) {
+	want := name
+	if !strings.Contains(want, ":") {
+		want += ":latest"
+	}
+	for i := range models {
+		if models[i].Name == name || models[i].Name == want {
+			return &models[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,148 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// modelServer serves the model endpoints from a mux
+func modelServer(t *testing.T, mux *http.ServeMux) *Client {
+	t.Helper()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return New(srv.URL)
+}
+
+// decodeModel reads the model name from a request body
+func decodeModel(t *testing.T, r *http.Request) string {
+	t.Helper()
+	var req modelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		t.Errorf("request body is not valid JSON: %v", err)
+	}
+	return req.Model
+}
+
+func TestList(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/tags", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"models":[
+			{"name":"llama3:latest","size":4661224676,"details":{"family":"llama","parameter_size":"8.0B","quantization_level":"Q4_0"}},
+			{"name":"qwen2.5:7b","size":4683087332}
+		]}`)
+	})
+	client := modelServer(t, mux)
+
+	models, err := client.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(models) != 2 || models[0].Name != "llama3:latest" || models[0].Size != 4661224676 || models[0].Details.ParameterSize != "8.0B" {
+		t.Fatalf("models = %+v", models)
+	}
+
+	for name, want := range map[string]bool{"llama3": true, "llama3:latest": true, "qwen2.5:7b": true, "qwen2.5": false, "llama3:70b": false} {
+		if _, ok := MatchModel(models, name); ok != want {
+			t.Errorf("MatchModel(%q) = %v, want %v", name, ok, want)
+		}
+	}
+}
+
+func TestShow(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/show", func(w http.ResponseWriter, r *http.Request) {
+		if name := decodeModel(t, r); name != "llama3" {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"error":"model '`+name+`' not found"}`)
+			return
+		}
+		io.WriteString(w, `{"parameters":"stop \"<|eot_id|>\"","details":{"family":"llama"},"model_info":{"llama.context_length":8192}}`)
+	})
+	client := modelServer(t, mux)
+
+	info, err := client.Show(context.Background(), "llama3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Details.Family != "llama" || info.Info["llama.context_length"] != float64(8192) {
+		t.Errorf("info = %+v", info)
+	}
+
+	_, err = client.Show(context.Background(), "nope")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "model 'nope' not found" {
+		t.Errorf("err = %v, want a 404 APIError", err)
+	}
+}
+
+func TestPull(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/pull", func(w http.ResponseWriter, r *http.Request) {
+		switch decodeModel(t, r) {
+		case "llama3":
+			io.WriteString(w, `{"status":"pulling manifest"}
+{"status":"pulling 6a0746a1ec1a","digest":"sha256:6a07","total":100,"completed":40}
+{"status":"pulling 6a0746a1ec1a","digest":"sha256:6a07","total":100,"completed":100}
+{"status":"verifying sha256 digest"}
+{"status":"success"}
+`)
+		case "broken":
+			io.WriteString(w, `{"status":"pulling manifest"}
+{"error":"pull model manifest: file does not exist"}
+`)
+		default:
+			io.WriteString(w, `{"status":"pulling manifest"}
+`)
+		}
+	})
+	client := modelServer(t, mux)
+
+	var updates []PullProgress
+	err := client.Pull(context.Background(), "llama3", func(p PullProgress) error {
+		updates = append(updates, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 5 || updates[1].Completed != 40 || updates[1].Total != 100 || updates[4].Status != "success" {
+		t.Errorf("updates = %+v", updates)
+	}
+
+	err = client.Pull(context.Background(), "broken", func(PullProgress) error { return nil })
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "pull model manifest: file does not exist" {
+		t.Errorf("err = %v, want the streamed error", err)
+	}
+
+	if err := client.Pull(context.Background(), "cut-short", func(PullProgress) error { return nil }); err == nil {
+		t.Error("expected an error for a pull without success")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	deleted := ""
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /api/delete", func(w http.ResponseWriter, r *http.Request) {
+		name := decodeModel(t, r)
+		if name != "llama3" {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"error":"model '`+name+`' not found"}`)
+			return
+		}
+		deleted = name
+	})
+	client := modelServer(t, mux)
+
+	if err := client.Delete(context.Background(), "llama3"); err != nil || deleted != "llama3" {
+		t.Fatalf("err = %v, deleted = %q", err, deleted)
+	}
+	if err := client.Delete(context.Background(), "nope"); err == nil {
+		t.Error("expected an error for a missing model")
+	}
+}
@@ -0,0 +1,241 @@
+// Package ollama is a small typed client for the Ollama chat API.
+//
+// Requests are JSON-encoded from structs, so prompts may contain any
+// characters, and error bodies returned by the server are surfaced as
+// *APIError values. Replies can be read whole with Chat or chunk by chunk,
+// as the server writes them, with ChatStream.
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is where a local Ollama server listens
+const DefaultBaseURL = "http://localhost:11434"
+
+// Message roles
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message is one turn of a conversation
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatRequest is the body of POST /api/chat
+type ChatRequest struct {
+	Model    string         `json:"model"`
+	Messages []Message      `json:"messages"`
+	Stream   bool           `json:"stream"`
+	Format   string         `json:"format,omitempty"` // "json" constrains the reply to valid JSON
+	Options  map[string]any `json:"options,omitempty"`
+}
+
+// ChatResponse is a reply of POST /api/chat, or one chunk of a streamed reply
+type ChatResponse struct {
+	Model      string    `json:"model"`
+	CreatedAt  time.Time `json:"created_at"`
+	Message    Message   `json:"message"`
+	Done       bool      `json:"done"`
+	DoneReason string    `json:"done_reason,omitempty"`
+
+	TotalDuration   time.Duration `json:"total_duration,omitempty"` // nanoseconds
+	PromptEvalCount int           `json:"prompt_eval_count,omitempty"`
+	EvalCount       int           `json:"eval_count,omitempty"`
+}
+
+// APIError is an error status returned by the server, with the message from
+// its {"error": "..."} body when there is one
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("ollama: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("ollama: %s (%d)", e.Message, e.StatusCode)
+}
+
+// Client talks to an Ollama server
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// New returns a client for the server at baseURL, or DefaultBaseURL when empty
+func New(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// Chat sends the conversation and returns the complete reply. Streaming is
+// turned off whatever req.Stream says.
+func (c *Client) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	req.Stream = false
+
+	var resp ChatResponse
+	if err := c.post(ctx, "/api/chat", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ChatStream sends the conversation with streaming on and calls fn with each
+// chunk as it arrives. The server writes one JSON object per line (NDJSON);
+// the last one has Done set and carries the statistics. The returned
+// response holds the whole message and the statistics of the last chunk.
+// Canceling ctx stops the stream and returns ctx's error.
+func (c *Client) ChatStream(ctx context.Context, req ChatRequest, fn func(ChatResponse) error) (*ChatResponse, error) {
+	req.Stream = true
+
+	var full ChatResponse
+	var content strings.Builder
+	err := c.stream(ctx, http.MethodPost, "/api/chat", req, func(line []byte) (bool, error) {
+		var chunk ChatResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return false, fmt.Errorf("ollama: invalid stream chunk: %w", err)
+		}
+		if err := fn(chunk); err != nil {
+			return false, err
+		}
+		content.WriteString(chunk.Message.Content)
+		full = chunk
+		return chunk.Done, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !full.Done {
+		return nil, fmt.Errorf("ollama: stream ended before the reply was done")
+	}
+
+	full.Message.Role = RoleAssistant
+	full.Message.Content = content.String()
+	return &full, nil
+}
+
+// post sends body as JSON and decodes the JSON reply into out
+func (c *Client) post(ctx context.Context, path string, body, out any) error {
+	resp, err := c.do(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("ollama: invalid response: %w", err)
+	}
+	return nil
+}
+
+// stream sends the request and calls fn with each line of the NDJSON reply
+// until fn reports it is done or the body ends. Errors reported mid-stream
+// as {"error": "..."} are returned as *APIError.
+func (c *Client) stream(ctx context.Context, method, path string, body any, fn func(line []byte) (bool, error)) error {
+	resp, err := c.do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var status struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(line, &status) == nil && status.Error != "" {
+			return &APIError{StatusCode: resp.StatusCode, Message: status.Error}
+		}
+
+		done, err := fn(line)
+		if err != nil || done {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("ollama: failed to read stream: %w", err)
+	}
+	return nil
+}
+
+// do sends body, if any, as JSON and returns the response, or an *APIError
+// for an error status. The caller closes the body.
+func (c *Client) do(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("ollama: failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("ollama: failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama: request failed: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return nil, readAPIError(resp)
+	}
+	return resp, nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// readAPIError builds an APIError from an error response. Ollama sends
+// {"error": "..."}; anything else is kept as plain text.
+func readAPIError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	var body struct {
+		Error string `json:"error"`
+	}
+	message := strings.TrimSpace(string(data))
+	if json.Unmarshal(data, &body) == nil && body.Error != "" {
+		message = body.Error
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: message}
+}
@@ -0,0 +1,206 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeServer answers /api/chat with handler after checking the request method
+func fakeServer(t *testing.T, handler func(w http.ResponseWriter, req ChatRequest)) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/chat" {
+			t.Errorf("got %s %s, want POST /api/chat", r.Method, r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		var req ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("request body is not valid JSON: %v", err)
+		}
+		handler(w, req)
+	}))
+	t.Cleanup(srv.Close)
+	return New(srv.URL + "/")
+}
+
+func TestChatEncodesAnyPrompt(t *testing.T) {
+	prompt := "say \"hi\" \\ then\na newline & a tab\t✓"
+
+	client := fakeServer(t, func(w http.ResponseWriter, req ChatRequest) {
+		if req.Model != "llama3" {
+			t.Errorf("model = %q, want llama3", req.Model)
+		}
+		if req.Stream {
+			t.Error("stream = true, want false")
+		}
+		if len(req.Messages) != 1 || req.Messages[0].Role != RoleUser || req.Messages[0].Content != prompt {
+			t.Errorf("messages = %+v, want one user message with the prompt", req.Messages)
+		}
+		w.Write([]byte(`{"model":"llama3","message":{"role":"assistant","content":"hi"},"done":true}`))
+	})
+
+	_, err := client.Chat(context.Background(), ChatRequest{
+		Model:    "llama3",
+		Messages: []Message{{Role: RoleUser, Content: prompt}},
+		Stream:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChatDecodesMessage(t *testing.T) {
+	client := fakeServer(t, func(w http.ResponseWriter, req ChatRequest) {
+		w.Write([]byte(`{
+			"model": "llama3",
+			"created_at": "2026-10-19T08:00:00Z",
+			"message": {"role": "assistant", "content": "Line one.\nLine \"two\"."},
+			"done": true,
+			"done_reason": "stop",
+			"eval_count": 12
+		}`))
+	})
+
+	resp, err := client.Chat(context.Background(), ChatRequest{Model: "llama3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Line one.\nLine \"two\"."; resp.Message.Content != want {
+		t.Errorf("content = %q, want %q", resp.Message.Content, want)
+	}
+	if resp.Message.Role != RoleAssistant || !resp.Done || resp.DoneReason != "stop" || resp.EvalCount != 12 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestChatErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		message string
+	}{
+		{"json error body", http.StatusNotFound, `{"error":"model \"nope\" not found, try pulling it first"}`, `model "nope" not found, try pulling it first`},
+		{"plain text body", http.StatusBadGateway, "upstream down\n", "upstream down"},
+		{"empty body", http.StatusInternalServerError, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fakeServer(t, func(w http.ResponseWriter, req ChatRequest) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := client.Chat(context.Background(), ChatRequest{Model: "nope"})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Message != tt.message {
+				t.Errorf("got %d %q, want %d %q", apiErr.StatusCode, apiErr.Message, tt.status, tt.message)
+			}
+		})
+	}
+}
+
+func TestChatInvalidResponse(t *testing.T) {
+	client := fakeServer(t, func(w http.ResponseWriter, req ChatRequest) {
+		w.Write([]byte(`<html>not json</html>`))
+	})
+
+	if _, err := client.Chat(context.Background(), ChatRequest{Model: "llama3"}); err == nil {
+		t.Fatal("expected an error for a non-JSON response")
+	}
+}
+
+func TestChatCanceled(t *testing.T) {
+	client := fakeServer(t, func(w http.ResponseWriter, req ChatRequest) {
+		t.Error("request should not reach the server")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Chat(ctx, ChatRequest{Model: "llama3"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestChatStream(t *testing.T) {
+	client := fakeServer(t, func(w http.ResponseWriter, req ChatRequest) {
+		if !req.Stream {
+			t.Error("stream = false, want true")
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, line := range []string{
+			`{"model":"llama3","message":{"role":"assistant","content":"Hel"},"done":false}`,
+			`{"model":"llama3","message":{"role":"assistant","content":"lo\n"},"done":false}`,
+			``,
+			`{"model":"llama3","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","eval_count":3}`,
+		} {
+			w.Write([]byte(line + "\n"))
+			w.(http.Flusher).Flush()
+		}
+	})
+
+	var chunks []string
+	resp, err := client.ChatStream(context.Background(), ChatRequest{Model: "llama3"}, func(chunk ChatResponse) error {
+		chunks = append(chunks, chunk.Message.Content)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 3 || chunks[0] != "Hel" || chunks[1] != "lo\n" {
+		t.Errorf("chunks = %q", chunks)
+	}
+	if resp.Message.Content != "Hello\n" || resp.Message.Role != RoleAssistant || resp.DoneReason != "stop" || resp.EvalCount != 3 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestChatStreamErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines string
+	}{
+		{"error chunk", `{"model":"llama3","message":{"content":"a"},"done":false}` + "\n" + `{"error":"model runner crashed"}` + "\n"},
+		{"cut short", `{"model":"llama3","message":{"content":"a"},"done":false}` + "\n"},
+		{"invalid chunk", "not json\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fakeServer(t, func(w http.ResponseWriter, req ChatRequest) {
+				w.Write([]byte(tt.lines))
+			})
+
+			_, err := client.ChatStream(context.Background(), ChatRequest{Model: "llama3"}, func(ChatResponse) error { return nil })
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestChatStreamStopsOnCallbackError(t *testing.T) {
+	client := fakeServer(t, func(w http.ResponseWriter, req ChatRequest) {
+		w.Write([]byte(`{"message":{"content":"a"},"done":false}` + "\n" + `{"message":{"content":"b"},"done":true}` + "\n"))
+	})
+
+	stop := errors.New("stop")
+	calls := 0
+	_, err := client.ChatStream(context.Background(), ChatRequest{Model: "llama3"}, func(ChatResponse) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("err = %v after %d calls, want stop after 1", err, calls)
+	}
+}
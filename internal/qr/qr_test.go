@@ -0,0 +1,171 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// The expected matrices come from an independent encoder (rsc.io/qr/coding)
+// with the same version, level and mask; # is a dark module
+
+var wantV1 = []string{
+	"#######..##.#.#######",
+	"#.....#..#.##.#.....#",
+	"#.###.#.#...#.#.###.#",
+	"#.###.#.#..#..#.###.#",
+	"#.###.#.###.#.#.###.#",
+	"#.....#.####..#.....#",
+	"#######.#.#.#.#######",
+	"........###..........",
+	"#.#####..#.#..#####..",
+	".#.#.#..#.######.####",
+	"####..#.....#.##.###.",
+	".###....#..####..####",
+	".#..#.#####.#..##....",
+	"........#.#.#..###.##",
+	"#######....#.#.....#.",
+	"#.....#.###....#..###",
+	"#.###.#.#.##.#..#..#.",
+	"#.###.#.#.########...",
+	"#.###.#.###.#.#..##..",
+	"#.....#...#####.###..",
+	"#######.#.#.#..#...#.",
+}
+
+var wantV7 = []string{
+	"#######..#..###....##.#...#.#.##....#.#######",
+	"#.....#..#..#...#..###..#.##...##..#..#.....#",
+	"#.###.#...###.#.####...##...###.##.#..#.###.#",
+	"#.###.#.#.#...###.##.....##.#...##.##.#.###.#",
+	"#.###.#.#..#.##....######....##...###.#.###.#",
+	"#.....#..##.#..###..#...#.###.........#.....#",
+	"#######.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#######",
+	".........#..##.####.#...##.##.#.#####........",
+	"##...###.##.#....##.######...#....#.....##...",
+	"..#..#.#.#...##.##.###...##############...##.",
+	"#.##.##..#..###..####..#####...#..#.###.#..#.",
+	"#..#.#..##...##..###.#..##.##..##..#.#.####..",
+	".....####..##.#####.#...##.######.##.#..##..#",
+	"#.......###..###.#.#.#.#.#.#.###.#..##.##.###",
+	"#...###.##.######.####.######..##.#.#.#.#.##.",
+	"##......#..#....#.#####...##.#...####.#..####",
+	"###.####.#..####.###..#.#.#..#.#.....##..#..#",
+	"####.#.#..##...###..#..#.#.####..#.###.##.###",
+	".#######...#####.###....#....#...#...#....#.#",
+	"###.#...##.##..###.#.#......##.###..##..###..",
+	"#.#########..#...##########...#.....######.#.",
+	"##.##...#...##.##.#.#...###.#.#..####...#.#..",
+	".##.#.#.#####..##.#.#.#.###.##....###.#.##.#.",
+	"..#.#...######.....##...#.#.##..##..#...#.##.",
+	".#..#############.#######.###.#.###.#####..##",
+	".#####..##.#.#.....##.#.#..##.###..#.#.#..###",
+	"#..####.###....#.##..#.#.###...#.####....#.#.",
+	"#.#.#....#.#...######.#####..#.#.###...#.##.#",
+	"#...###..###.#....#....###...###.#..#####...#",
+	".###...#..###.##...#######...###.#.#.###....#",
+	"#.##.###.#......#..##.#....###..##..#.#..#..#",
+	"..#....####.#.##.#.###.##.####..####..#...##.",
+	"##..#.#..######.###.#..#.##..#.#.##.#####..##",
+	"...###....#.#.###..#.#.#.###.###.########....",
+	"....#.#########.###.#....##....####.##...###.",
+	".####..####.###..##.##.##..####.#.###########",
+	"#..##.##..##..#############.#.#.##########...",
+	"........#####.##.#.##...##..######.##...##.##",
+	"#######.#####..#...##.#.###.......#.#.#.##...",
+	"#.....#.#.#######..##...#.#..#...####...#.#.#",
+	"#.###.#..#..#..####.######....##..#.#####..#.",
+	"#.###.#..###.#.#....##.###.#######.#.##.#.#..",
+	"#.###.#..###.#..#####...#....#...#..###.#.##.",
+	"#.....#.#..#..##.#..##......##.###..##.##.#..",
+	"#######.###..#.#.###.####..#...#....#.#.#..#.",
+}
+
+func TestEncodeKnownAnswers(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		level   Level
+		mask    int
+		version int
+		want    []string
+	}{
+		{"v1", "sun-cli", Medium, 2, 1, wantV1},
+		{"v7", "https://console.cloud.google.com/logs/query;query=severity%3E%3DERROR?project=airasia-move-prod&authuser=0&hl=en&pli=1&supportedpurview=project", Low, 5, 7, wantV7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := encode([]byte(tt.text), tt.level, tt.mask)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Version != tt.version || c.Size != len(tt.want) {
+				t.Fatalf("version %d size %d, want version %d size %d", c.Version, c.Size, tt.version, len(tt.want))
+			}
+			for y, row := range tt.want {
+				var got strings.Builder
+				for x := 0; x < c.Size; x++ {
+					if c.Dark(x, y) {
+						got.WriteByte('#')
+					} else {
+						got.WriteByte('.')
+					}
+				}
+				if got.String() != row {
+					t.Errorf("row %2d = %s\n      want %s", y, got.String(), row)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeCapacity(t *testing.T) {
+	// Byte mode capacity of a version 40 code per level
+	capacity := map[Level]int{Low: 2953, Medium: 2331, Quartile: 1663, High: 1273}
+
+	for level, n := range capacity {
+		c, err := Encode(strings.Repeat("a", n), level)
+		if err != nil {
+			t.Errorf("level %d, %d bytes: %v", level, n, err)
+		} else if c.Version != 40 {
+			t.Errorf("level %d, %d bytes: version %d, want 40", level, n, c.Version)
+		}
+
+		if _, err := Encode(strings.Repeat("a", n+1), level); !errors.Is(err, ErrTooLong) {
+			t.Errorf("level %d, %d bytes: err = %v, want ErrTooLong", level, n+1, err)
+		}
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	c, err := Encode("https://console.cloud.google.com", Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const scale = 3
+	var buf bytes.Buffer
+	if err := c.WritePNG(&buf, scale); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("not a valid PNG: %v", err)
+	}
+
+	side := (c.Size + 2*QuietZone) * scale
+	if b := img.Bounds(); b.Dx() != side || b.Dy() != side {
+		t.Fatalf("size %dx%d, want %dx%d", b.Dx(), b.Dy(), side, side)
+	}
+	for y := 0; y < c.Size+2*QuietZone; y++ {
+		for x := 0; x < c.Size+2*QuietZone; x++ {
+			r, _, _, _ := img.At(x*scale+scale-1, y*scale).RGBA()
+			if dark := r == 0; dark != c.Dark(x-QuietZone, y-QuietZone) {
+				t.Fatalf("module (%d, %d) dark = %v in the PNG", x-QuietZone, y-QuietZone, dark)
+			}
+		}
+	}
+}
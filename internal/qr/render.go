@@ -0,0 +1,63 @@
+package qr
+
+import (
+	"bufio"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// QuietZone is the light border, in modules, that scanners need around a code
+const QuietZone = 4
+
+// WriteTerminal renders the code with Unicode half blocks, two module rows per
+// text line. Colours are set explicitly so the code scans on dark and light
+// terminal themes alike.
+func (c *Code) WriteTerminal(w io.Writer) error {
+	const (
+		upperHalf = "▀"
+		reset     = "\033[0m"
+	)
+	// Foreground paints the upper module, background the lower one
+	fg := map[bool]string{true: "\033[30m", false: "\033[97m"}
+	bg := map[bool]string{true: "\033[40m", false: "\033[107m"}
+
+	bw := bufio.NewWriter(w)
+	from, to := -QuietZone, c.Size+QuietZone
+	for y := from; y < to; y += 2 {
+		// Colours are only written when they change along the line
+		var top, bottom, started bool
+		for x := from; x < to; x++ {
+			t, b := c.Dark(x, y), c.Dark(x, y+1)
+			if !started || t != top {
+				bw.WriteString(fg[t])
+			}
+			if !started || b != bottom {
+				bw.WriteString(bg[b])
+			}
+			top, bottom, started = t, b, true
+			bw.WriteString(upperHalf)
+		}
+		bw.WriteString(reset + "\n")
+	}
+	return bw.Flush()
+}
+
+// WritePNG renders the code as a black-and-white PNG with scale pixels per module
+func (c *Code) WritePNG(w io.Writer, scale int) error {
+	if scale < 1 {
+		scale = 1
+	}
+	side := (c.Size + 2*QuietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+
+	for py := 0; py < side; py++ {
+		for px := 0; px < side; px++ {
+			if c.Dark(px/scale-QuietZone, py/scale-QuietZone) {
+				img.SetColorIndex(px, py, 1)
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
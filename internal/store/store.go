@@ -0,0 +1,262 @@
+// Package store persists CLI state files safely.
+//
+// Writes go to a temporary file in the same directory, are fsynced and then
+// renamed over the target, all while holding an advisory lock on a sidecar
+// "<file>.lock". Readers therefore never see a half-written file, and two
+// processes writing at once cannot interleave. UpdateJSON holds the lock
+// across a whole read-modify-write. Files owned by other programs use
+// Replace, which skips the lock.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CorruptError is returned by ReadJSON when a file cannot be parsed. The
+// broken file has been moved aside to Backup so the caller can start fresh.
+type CorruptError struct {
+	Path   string
+	Backup string
+	Err    error
+}
+
+func (e *CorruptError) Error() string {
+	return fmt.Sprintf("%s is corrupted (moved to %s): %v", e.Path, e.Backup, e.Err)
+}
+
+func (e *CorruptError) Unwrap() error {
+	return e.Err
+}
+
+// Lock takes an exclusive advisory lock for path, blocking until it is
+// available. The returned function releases it. A symlink shares the lock
+// of the file it points to.
+func Lock(path string) (func(), error) {
+	path = resolveSymlinks(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	f, err := os.OpenFile(lockPath(path), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// WriteFile atomically replaces path with data under the file's lock
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	path = resolveSymlinks(path)
+
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return writeAtomic(path, data, perm)
+}
+
+// Replace atomically replaces path with data without taking the lock. It is
+// for files other programs own and lock their own way, such as kubeconfig:
+// kubectl treats an existing "<file>.lock" as held, so Lock must never
+// create one next to them.
+func Replace(path string, data []byte, perm os.FileMode) error {
+	return writeAtomic(resolveSymlinks(path), data, perm)
+}
+
+// WriteJSON atomically writes v as indented JSON
+func WriteJSON(path string, v any, perm os.FileMode) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+	return WriteFile(path, data, perm)
+}
+
+// Append appends data to path under the file's lock, creating the file if
+// needed. It suits append-only logs where every write is one complete line.
+func Append(path string, data []byte, perm os.FileMode) error {
+	path = resolveSymlinks(path)
+
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, perm)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to append to %s: %w", filepath.Base(path), err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync %s: %w", filepath.Base(path), err)
+	}
+	return f.Close()
+}
+
+// ReadJSON reads path into v. If the file exists but is not valid JSON, it is
+// moved aside and a *CorruptError is returned; a missing file returns an
+// error satisfying errors.Is(err, fs.ErrNotExist).
+func ReadJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return quarantine(path, v, err)
+	}
+	return nil
+}
+
+// UpdateJSON reads path into v, calls update and writes v back as indented
+// JSON, holding the file's lock throughout so that concurrent
+// read-modify-write cycles cannot lose each other's changes. A missing file
+// leaves v as it is; a corrupt one is moved aside and returned as a
+// *CorruptError without calling update.
+func UpdateJSON(path string, v any, perm os.FileMode, update func() error) error {
+	path = resolveSymlinks(path)
+
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(data, v); err != nil {
+			return moveAside(path, err)
+		}
+	}
+
+	if err := update(); err != nil {
+		return err
+	}
+
+	data, err = json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+	return writeAtomic(path, data, perm)
+}
+
+// writeAtomic writes data to a temp file next to path, fsyncs and renames it
+func writeAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	cleanup := func() { _ = os.Remove(tmpName) }
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		cleanup()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		cleanup()
+		return fmt.Errorf("failed to sync %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		cleanup()
+		return fmt.Errorf("failed to replace %s: %w", filepath.Base(path), err)
+	}
+
+	syncDir(dir)
+	return nil
+}
+
+// syncDir fsyncs a directory so a rename survives a crash (best effort;
+// not supported on every platform)
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	d.Close()
+}
+
+// quarantine moves a corrupted file aside, keeping it for inspection. The
+// file is read again under the lock first: another process may have
+// replaced it with a good version since it was read, and then v is filled
+// from that instead.
+func quarantine(path string, v any, cause error) error {
+	unlock, err := Lock(path)
+	if err != nil {
+		return errors.Join(cause, err)
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return moveAside(path, err)
+	}
+	return nil
+}
+
+// moveAside renames a corrupted file to a timestamped backup; the caller
+// holds the file's lock
+func moveAside(path string, cause error) error {
+	backup := fmt.Sprintf("%s.corrupt-%s", path, time.Now().Format("20060102-150405"))
+	if err := os.Rename(path, backup); err != nil {
+		return fmt.Errorf("%s is corrupted and could not be moved aside: %w", path, errors.Join(cause, err))
+	}
+	return &CorruptError{Path: path, Backup: backup, Err: cause}
+}
+
+// resolveSymlinks returns the file path points to, so that writes go through
+// symlinks (e.g. a dotfiles-managed kubeconfig) instead of replacing them
+func resolveSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// lockPath returns the sidecar lock file for path
+func lockPath(path string) string {
+	return path + ".lock"
+}
@@ -0,0 +1,289 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWriteFileReplacesAtomically(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "state.json")
+
+	if err := WriteFile(path, []byte("first"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, []byte("second"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "second" {
+		t.Errorf("content = %q, want second", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("perm = %o, want 600", perm)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Errorf("temp file %s left behind", e.Name())
+		}
+	}
+}
+
+func TestWriteFileFollowsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "real")
+	link := filepath.Join(dir, "link")
+	if err := os.WriteFile(target, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	if err := WriteFile(link, []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&fs.ModeSymlink == 0 {
+		t.Errorf("link was replaced by a regular file")
+	}
+	if data, _ := os.ReadFile(target); string(data) != "new" {
+		t.Errorf("target content = %q, want new", data)
+	}
+}
+
+func TestUpdateJSONFollowsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "real.json")
+	link := filepath.Join(dir, "link.json")
+	if err := WriteJSON(target, map[string]int{"a": 1}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	var v map[string]int
+	err := UpdateJSON(link, &v, 0644, func() error {
+		v["a"]++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(link, []byte("\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&fs.ModeSymlink == 0 {
+		t.Errorf("link was replaced by a regular file")
+	}
+	v = nil
+	if err := ReadJSON(target, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v["a"] != 2 {
+		t.Errorf("target = %v, want a=2", v)
+	}
+	if _, err := os.Stat(lockPath(link)); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("lock taken on the link instead of its target (err = %v)", err)
+	}
+	if _, err := os.Stat(lockPath(target)); err != nil {
+		t.Errorf("no lock file for the target: %v", err)
+	}
+}
+
+func TestReplaceLeavesNoLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+
+	if err := Replace(path, []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != "data" {
+		t.Errorf("content = %q, want data", data)
+	}
+	if _, err := os.Stat(lockPath(path)); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("lock file exists after Replace (err = %v)", err)
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("missing", func(t *testing.T) {
+		var v map[string]int
+		err := ReadJSON(filepath.Join(dir, "missing.json"), &v)
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("err = %v, want fs.ErrNotExist", err)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		path := filepath.Join(dir, "valid.json")
+		if err := WriteJSON(path, map[string]int{"a": 1}, 0644); err != nil {
+			t.Fatal(err)
+		}
+		var v map[string]int
+		if err := ReadJSON(path, &v); err != nil {
+			t.Fatal(err)
+		}
+		if v["a"] != 1 {
+			t.Errorf("v = %v, want a=1", v)
+		}
+	})
+
+	t.Run("corrupt", func(t *testing.T) {
+		path := filepath.Join(dir, "corrupt.json")
+		if err := os.WriteFile(path, []byte(`{"a": `), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		var v map[string]int
+		err := ReadJSON(path, &v)
+		var corrupt *CorruptError
+		if !errors.As(err, &corrupt) {
+			t.Fatalf("err = %v, want *CorruptError", err)
+		}
+		if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("corrupt file still at %s", path)
+		}
+		data, err := os.ReadFile(corrupt.Backup)
+		if err != nil {
+			t.Fatalf("backup not readable: %v", err)
+		}
+		if string(data) != `{"a": ` {
+			t.Errorf("backup content = %q, want the corrupt file", data)
+		}
+	})
+}
+
+func TestQuarantineKeepsRepairedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	// Another process rewrote the file after it was read as corrupt
+	if err := os.WriteFile(path, []byte(`{"a": 2}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var v map[string]int
+	if err := quarantine(path, &v, errors.New("unexpected end of JSON input")); err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+	if v["a"] != 2 {
+		t.Errorf("v = %v, want a=2 from the rewritten file", v)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("file was moved aside: %v", err)
+	}
+}
+
+func TestAppendConcurrentLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log", "usage.jsonl")
+	const writers, lines = 8, 50
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < lines; i++ {
+				line := fmt.Sprintf("%d-%d %s\n", w, i, strings.Repeat("x", 500))
+				if err := Append(path, []byte(line), 0644); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+	if len(got) != writers*lines {
+		t.Fatalf("got %d lines, want %d", len(got), writers*lines)
+	}
+	for _, line := range got {
+		if !bytes.HasSuffix(line, []byte(strings.Repeat("x", 500))) {
+			t.Fatalf("interleaved line: %.40q", line)
+		}
+	}
+}
+
+func TestUpdateJSONConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter.json")
+	const n = 20
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var counter struct{ N int }
+			err := UpdateJSON(path, &counter, 0644, func() error {
+				counter.N++
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var counter struct{ N int }
+	if err := ReadJSON(path, &counter); err != nil {
+		t.Fatal(err)
+	}
+	if counter.N != n {
+		t.Errorf("N = %d, want %d: updates were lost", counter.N, n)
+	}
+}
+
+func TestUpdateJSONErrorKeepsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := WriteJSON(path, map[string]int{"a": 1}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var v map[string]int
+	want := errors.New("declined")
+	err := UpdateJSON(path, &v, 0644, func() error {
+		v["a"] = 2
+		return want
+	})
+	if !errors.Is(err, want) {
+		t.Fatalf("err = %v, want %v", err, want)
+	}
+
+	v = nil
+	if err := ReadJSON(path, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v["a"] != 1 {
+		t.Errorf("v = %v, want the file unchanged", v)
+	}
+}